@@ -23,7 +23,7 @@ EXAMPLE
 
 CONFIGURATION
 
-	supercheck stores a MASTER.SCP file in ~/.config/hamradio. The file is automatically updated if
+	supercheck stores a MASTER.SCP file in $XDG_CONFIG_HOME/hamradio or ~/.config/hamradio. The file is automatically updated if
 	there is a newer version available at http://www.supercheckpartial.com/MASTER.SCP.
 
 	The call history files must be downloaded manually from https://n1mmwp.hamdocs.com/mmfiles/categories/callhistory/
@@ -70,6 +70,7 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer database.Close()
 
 	matches, err := database.Find(options.Args.Input[0])
 	if err != nil {
@@ -0,0 +1,67 @@
+package scp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ADIFFieldSet defines the fields that are read from an ADIF log. The fields are mapped from the ADIF fields
+// NAME -> Name, STATE -> State, GRIDSQUARE -> Grid, and DXCC -> DXCC.
+var ADIFFieldSet = FieldSet{FieldCall, FieldUserName, FieldState, FieldGrid, FieldDXCC}
+
+// ReadADIF creates a new Database and fills it with the callsigns of all QSO records in the ADIF log that is
+// read with the given reader. If a callsign appears in more than one record, the field values of the last record are used.
+func ReadADIF(r io.Reader) (*Database, error) {
+	database := NewDatabase(ADIFFieldSet...)
+	in := bufio.NewReader(r)
+	record := make(map[string]string)
+	for {
+		_, err := in.ReadString('<')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		specifier, err := in.ReadString('>')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		parts := strings.Split(strings.TrimSuffix(specifier, ">"), ":")
+		name := strings.ToUpper(strings.TrimSpace(parts[0]))
+		switch name {
+		case "EOH":
+			record = make(map[string]string)
+			continue
+		case "EOR":
+			if call := strings.TrimSpace(record["CALL"]); call != "" {
+				database.Add(call, call, record["NAME"], record["STATE"], record["GRIDSQUARE"], record["DXCC"])
+			}
+			record = make(map[string]string)
+			continue
+		}
+		if len(parts) < 2 {
+			continue
+		}
+		length, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("invalid ADIF field specifier <%s", specifier)
+		}
+		// the data is copied piece by piece, a corrupt length must not allocate more memory than the actual data needs
+		data := new(strings.Builder)
+		_, err = io.CopyN(data, in, int64(length))
+		if err != nil {
+			return nil, fmt.Errorf("truncated ADIF field %s: %v", name, err)
+		}
+		record[name] = data.String()
+	}
+
+	return database, nil
+}
@@ -0,0 +1,42 @@
+package scp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadADIF(t *testing.T) {
+	const testADIF = `exported for testing <CALL:4>XXXX
+<ADIF_VER:5>3.1.0 <EOH>
+<CALL:6>DL3NEY <NAME:7>Florian <GRIDSQUARE:6>JO62qm <DXCC:3>230 <QSO_DATE:8:D>20230301 <EOR>
+<call:5>K1ABC <state:2>CT <eor>
+<CALL:5>K1ABC <NAME:4>John <STATE:2>CT <EOR>
+<NAME:6>Nobody <EOR>
+`
+
+	database, err := ReadADIF(strings.NewReader(testADIF))
+	require.NoError(t, err)
+	assert.Equal(t, ADIFFieldSet, database.FieldSet())
+
+	entries := database.entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "DL3NEY", entries[0].Key())
+	assert.Equal(t, []string{"Florian", "", "JO62qm", "230"}, entries[0].GetValues(FieldUserName, FieldState, FieldGrid, FieldDXCC))
+	assert.Equal(t, "K1ABC", entries[1].Key())
+	assert.Equal(t, []string{"John", "CT", "", ""}, entries[1].GetValues(FieldUserName, FieldState, FieldGrid, FieldDXCC))
+}
+
+func TestReadADIF_Invalid(t *testing.T) {
+	_, err := ReadADIF(strings.NewReader("<CALL:x>DL3NEY <EOR>"))
+	assert.Error(t, err)
+
+	_, err = ReadADIF(strings.NewReader("<CALL:10>DL3NEY"))
+	assert.Error(t, err)
+
+	_, err = ReadADIF(strings.NewReader("<CALL:2000000000>DL3NEY <EOR>"))
+	require.Error(t, err, "corrupt length")
+	assert.Contains(t, err.Error(), "truncated ADIF field CALL")
+}
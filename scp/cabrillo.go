@@ -0,0 +1,42 @@
+package scp
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ReadCabrillo creates a new Database and fills it with the callsigns from the QSO: lines of the Cabrillo log
+// that is read with the given reader. Both the callsign of the logging station and the callsign of the worked
+// station are added. All other lines are ignored.
+func ReadCabrillo(r io.Reader) (*Database, error) {
+	database := NewDatabase()
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		sent, received, ok := parseCabrilloQSO(lines.Text())
+		if !ok {
+			continue
+		}
+		database.Add(sent)
+		database.Add(received)
+	}
+	if err := lines.Err(); err != nil {
+		return nil, err
+	}
+	return database, nil
+}
+
+// parseCabrilloQSO returns the callsigns of the logging and of the worked station from the given QSO: line.
+// A QSO line consists of frequency, mode, date, time, the sent callsign and exchange, the received callsign and exchange,
+// and an optional transmitter ID. Since the sent and the received exchange have the same number of fields, the received
+// callsign is in the middle of the remaining fields.
+func parseCabrilloQSO(line string) (string, string, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 7 || !strings.EqualFold(fields[0], "QSO:") {
+		return "", "", false
+	}
+	sent := fields[5]
+	remaining := fields[6:]
+	received := remaining[(len(remaining)-1)/2]
+	return sent, received, true
+}
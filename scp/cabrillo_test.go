@@ -0,0 +1,52 @@
+package scp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCabrilloQSO(t *testing.T) {
+	tt := []struct {
+		line           string
+		sent, received string
+		valid          bool
+	}{
+		{"QSO:  3799 PH 1999-03-06 0711 HC8N          59  700    W1AW          59  CT", "HC8N", "W1AW", true},
+		{"QSO:  3799 PH 1999-03-06 0711 HC8N          59  700    W1AW          59  CT     0", "HC8N", "W1AW", true},
+		{"QSO: 14042 CW 2023-02-18 1200 DL3NEY 599 14 K1ABC 599 CT", "DL3NEY", "K1ABC", true},
+		{"QSO: 14042 CW 2023-02-18 1200 DL3NEY K1ABC", "DL3NEY", "K1ABC", true},
+		{"qso: 50125 PH 2023-06-10 1800 W1AW FN31 K1ABC FN32", "W1AW", "K1ABC", true},
+		{"CALLSIGN: DL3NEY", "", "", false},
+		{"QSO: 14042 CW 2023-02-18 1200 DL3NEY", "", "", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.line, func(t *testing.T) {
+			sent, received, valid := parseCabrilloQSO(tc.line)
+			assert.Equal(t, tc.valid, valid)
+			assert.Equal(t, tc.sent, sent)
+			assert.Equal(t, tc.received, received)
+		})
+	}
+}
+
+func TestReadCabrillo(t *testing.T) {
+	const testCabrillo = `START-OF-LOG: 3.0
+CALLSIGN: DL3NEY
+CONTEST: CQ-WW-CW
+QSO: 14042 CW 2023-02-18 1200 DL3NEY 599 14 K1ABC 599 05
+QSO: 14042 CW 2023-02-18 1201 DL3NEY 599 14 W1AW 599 05
+END-OF-LOG:`
+
+	database, err := ReadCabrillo(strings.NewReader(testCabrillo))
+	require.NoError(t, err)
+
+	entries := database.entries()
+	keys := make([]string, len(entries))
+	for i, entry := range entries {
+		keys[i] = entry.Key()
+	}
+	assert.Equal(t, []string{"DL3NEY", "K1ABC", "W1AW"}, keys)
+}
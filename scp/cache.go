@@ -0,0 +1,97 @@
+package scp
+
+import (
+	"container/list"
+	"sync"
+)
+
+// matchCache keeps the matches of the most recently used search inputs. If the cache is full, the least recently
+// used search input is dropped.
+type matchCache struct {
+	lock  sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type cachedMatches struct {
+	key     string
+	matches []Match
+}
+
+func newMatchCache(size int) *matchCache {
+	return &matchCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns a copy of the cached matches for the given key.
+func (c *matchCache) get(key string) ([]Match, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	matches := element.Value.(*cachedMatches).matches
+	return append([]Match(nil), matches...), true
+}
+
+func (c *matchCache) put(key string, matches []Match) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	matches = append([]Match(nil), matches...)
+	if element, ok := c.items[key]; ok {
+		element.Value.(*cachedMatches).matches = matches
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&cachedMatches{key: key, matches: matches})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cachedMatches).key)
+	}
+}
+
+func (c *matchCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.order.Init()
+	c.items = make(map[string]*list.Element, c.size)
+}
+
+// SetCacheSize enables a cache for the results of Find, FindContext, and FindStrings with the given number of search
+// inputs. If the cache is full, the results of the least recently used search input are dropped. The cache is cleared
+// whenever the database is modified. A size of zero or less disables the cache, which is the default.
+func (d *Database) SetCacheSize(size int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if size <= 0 {
+		d.cache = nil
+		return
+	}
+	d.cache = newMatchCache(size)
+}
+
+// invalidate clears the cached index and search results after the database was modified.
+func (d *Database) invalidate() {
+	d.index.invalidate()
+	d.cache.invalidate()
+}
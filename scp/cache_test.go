@@ -0,0 +1,73 @@
+package scp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchCache(t *testing.T) {
+	cache := newMatchCache(2)
+	cache.put("A", []Match{{Entry: newEntry("A", nil)}})
+	cache.put("B", []Match{{Entry: newEntry("B", nil)}})
+	_, ok := cache.get("A")
+	require.True(t, ok)
+
+	cache.put("C", []Match{{Entry: newEntry("C", nil)}})
+	_, ok = cache.get("B")
+	assert.False(t, ok, "least recently used is dropped")
+	matches, ok := cache.get("A")
+	assert.True(t, ok)
+	assert.Equal(t, "A", matches[0].Key())
+	_, ok = cache.get("C")
+	assert.True(t, ok)
+
+	matches[0] = Match{}
+	matches, _ = cache.get("A")
+	assert.Equal(t, "A", matches[0].Key(), "returns a copy")
+
+	cache.invalidate()
+	_, ok = cache.get("A")
+	assert.False(t, ok)
+
+	var disabled *matchCache
+	disabled.put("A", nil)
+	_, ok = disabled.get("A")
+	assert.False(t, ok)
+}
+
+func TestDatabase_SetCacheSize(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.SetCacheSize(10)
+
+	actual, err := database.FindStrings("DL2ABC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC"}, actual)
+	_, ok := database.cache.get("DL2ABC")
+	assert.True(t, ok)
+
+	modifications := []func(){
+		func() { database.Add("DL2ABC") },
+		func() { database.Remove("DL1ABC") },
+		func() { database.Clear() },
+	}
+	expected := [][]string{
+		{"DL2ABC", "DL1ABC"},
+		{"DL2ABC"},
+		{},
+	}
+	for i, modify := range modifications {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			modify()
+			actual, err := database.FindStrings("DL2ABC")
+			require.NoError(t, err)
+			assert.Equal(t, expected[i], actual)
+		})
+	}
+
+	database.SetCacheSize(0)
+	assert.Nil(t, database.cache)
+}
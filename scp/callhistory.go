@@ -1,6 +1,8 @@
 package scp
 
 import (
+	"bufio"
+	"fmt"
 	"io"
 	"strings"
 )
@@ -11,27 +13,118 @@ var DefaultFieldSet = NewFieldSet("Call", "Name", "Loc1", "Loc2", "Sect", "State
 const (
 	FieldCall     FieldName = "Call"
 	FieldUserName FieldName = "Name"
+	// FieldUserText contains arbitrary notes of the user. The value is not interpreted in any way.
 	FieldUserText FieldName = "UserText"
 	FieldIgnore   FieldName = ""
+
+	// FieldOpName contains the operator's name, it is the same field as FieldUserName.
+	FieldOpName = FieldUserName
+	// FieldState contains the abbreviation of the US state or the Canadian province, e.g. "OH".
+	FieldState FieldName = "State"
+	// FieldCQZone contains the number of the CQ zone as string, e.g. "14".
+	FieldCQZone FieldName = "CQZone"
+	// FieldITUZone contains the number of the ITU zone as string, e.g. "28".
+	FieldITUZone FieldName = "ITUZone"
+	// FieldGrid contains the maidenhead locator, e.g. "JO62" or "JO62qm" (see ValidGrid).
+	FieldGrid FieldName = "Grid"
+	// FieldCheck contains the check of the ARRL Sweepstakes, the last two digits of the year of the first license, e.g. "85".
+	FieldCheck FieldName = "CK"
+	// FieldSection contains the ARRL or RAC section, e.g. "EMA" or "STX".
+	FieldSection FieldName = "Sect"
+	// FieldClass contains the ARRL Field Day class, the number of transmitters and the category, e.g. "2A".
+	FieldClass FieldName = "Class"
+	// FieldDXCC contains the number of the DXCC entity as string, e.g. "230" for Germany.
+	FieldDXCC FieldName = "DXCC"
 )
 
 // ReadCallHistory creates a new Database and fills it from the call history that is read with the given reader.
 func ReadCallHistory(r io.Reader) (*Database, error) {
-	parser := NewCallHistoryParser()
+	return ReadCallHistoryWithDelimiter(r, "")
+}
+
+// ReadCallHistoryWithDelimiter creates a new Database and fills it from the call history that is read with the given reader.
+// The values in each line are separated by the given delimiter. If the delimiter is empty, it is detected automatically
+// (see NewCallHistoryParserWithDelimiter).
+func ReadCallHistoryWithDelimiter(r io.Reader, delimiter string) (*Database, error) {
+	parser := NewCallHistoryParserWithDelimiter(delimiter)
 	result, err := Read(r, parser)
+	if err != nil {
+		return nil, err
+	}
 	result.fieldSet = parser.fieldSet
-	return result, err
+	return result, nil
+}
+
+// callHistoryReservedChars are the characters that separate the values and lines in a call history file.
+const callHistoryReservedChars = ",;\r\n"
+
+// WriteCallHistory writes the database to a writer using the call history format. The field set of the database
+// is written as !!Order!! directive, followed by one line per entry with the values of all fields in the order
+// of the field set. Fields that are ignored are written as empty values. The entries are written in alphabetical order.
+// The call history format cannot escape values, WriteCallHistory returns an error if a value contains a comma,
+// a semicolon or a line break.
+func WriteCallHistory(w io.Writer, d *Database) error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	fieldSet := d.fieldSet
+	if fieldSet.CallIndex() < 0 {
+		fieldSet = append(FieldSet{FieldCall}, fieldSet...)
+	}
+
+	out := bufio.NewWriter(w)
+	_, err := fmt.Fprintf(out, "!!Order!!,%s\n", strings.Join(fieldSet.Names(), ","))
+	if err != nil {
+		return err
+	}
+	values := make([]string, len(fieldSet))
+	for _, entry := range d.entries() {
+		for i, fieldName := range fieldSet {
+			switch fieldName {
+			case FieldCall:
+				values[i] = entry.key
+			case FieldIgnore:
+				values[i] = ""
+			default:
+				values[i] = entry.Get(fieldName)
+			}
+			if strings.ContainsAny(values[i], callHistoryReservedChars) {
+				return fmt.Errorf("cannot write %s: value %q of field %s contains a delimiter", entry.key, values[i], fieldName)
+			}
+		}
+		_, err := fmt.Fprintln(out, strings.Join(values, ","))
+		if err != nil {
+			return err
+		}
+	}
+	return out.Flush()
 }
 
 // CallHistoryParser is used to parse the entries in a call history file to fill the database.
 type CallHistoryParser struct {
-	fieldSet FieldSet
+	fieldSet  FieldSet
+	delimiter string
 }
 
 // NewCallHistoryParser creates a new CallHistoryParser that uses the DefaultFieldSet.
+// The delimiter between the values is detected automatically.
 func NewCallHistoryParser() *CallHistoryParser {
+	return NewCallHistoryParserWithDelimiter("")
+}
+
+// NewCallHistoryParserWithDelimiter creates a new CallHistoryParser that uses the DefaultFieldSet and
+// the given delimiter between the values in each line, e.g. "\t" for tab separated files.
+// If the delimiter is empty, the values are separated by semicolons if a line contains any, otherwise by commas.
+func NewCallHistoryParserWithDelimiter(delimiter string) *CallHistoryParser {
+	return newCallHistoryParser(DefaultFieldSet, delimiter)
+}
+
+// newCallHistoryParser creates a new CallHistoryParser that uses the given field set until the file defines
+// its own field set with an !!Order!! directive.
+func newCallHistoryParser(fieldSet FieldSet, delimiter string) *CallHistoryParser {
 	return &CallHistoryParser{
-		fieldSet: DefaultFieldSet,
+		fieldSet:  fieldSet,
+		delimiter: delimiter,
 	}
 }
 
@@ -42,8 +135,8 @@ func (p *CallHistoryParser) ParseEntry(line string) (Entry, bool) {
 	switch {
 	case strings.HasPrefix(line, "#"):
 		return Entry{}, false
-	case strings.HasPrefix(line, "!!Order!!,"):
-		p.handleFieldSetDirective(line[10:])
+	case strings.HasPrefix(line, "!!Order!!"):
+		p.handleFieldSetDirective(line[9:])
 		return Entry{}, false
 	case strings.HasPrefix(line, "!!"): // any other directives are currently ignored
 		return Entry{}, false
@@ -53,19 +146,29 @@ func (p *CallHistoryParser) ParseEntry(line string) (Entry, bool) {
 }
 
 func (p *CallHistoryParser) handleFieldSetDirective(line string) {
-	fieldNames := strings.Split(line, ";")
-	if len(fieldNames) <= 1 {
-		fieldNames = strings.Split(line, ",")
+	switch {
+	case p.delimiter != "" && strings.HasPrefix(line, p.delimiter):
+		line = line[len(p.delimiter):]
+	case strings.HasPrefix(line, ","), p.delimiter == "" && strings.HasPrefix(line, ";"):
+		line = line[1:]
 	}
 
-	p.fieldSet = NewFieldSet(fieldNames...)
+	p.fieldSet = NewFieldSet(p.split(line)...)
 }
 
-func (p *CallHistoryParser) parseEntry(line string) (Entry, bool) {
+func (p *CallHistoryParser) split(line string) []string {
+	if p.delimiter != "" {
+		return strings.Split(line, p.delimiter)
+	}
 	values := strings.Split(line, ";")
 	if len(values) <= 1 {
 		values = strings.Split(line, ",")
 	}
+	return values
+}
+
+func (p *CallHistoryParser) parseEntry(line string) (Entry, bool) {
+	values := p.split(line)
 	callIndex := p.fieldSet.CallIndex()
 	if callIndex < 0 || callIndex >= len(values) {
 		return Entry{}, false
@@ -121,6 +224,15 @@ func (s FieldSet) Get(index int) FieldName {
 	return s[index]
 }
 
+// Names returns the names of all fields in this FieldSet as strings.
+func (s FieldSet) Names() []string {
+	result := make([]string, len(s))
+	for i, fieldName := range s {
+		result[i] = string(fieldName)
+	}
+	return result
+}
+
 // UsableNames returns a slice of usable field names (excluding Call and empty field names).
 func (s FieldSet) UsableNames() []FieldName {
 	result := make([]FieldName, 0, len(s))
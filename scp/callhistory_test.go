@@ -1,7 +1,9 @@
 package scp
 
 import (
+	"bytes"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -65,6 +67,35 @@ func TestParseEntry(t *testing.T) {
 	}
 }
 
+func TestParseEntryWithDelimiter(t *testing.T) {
+	tt := []struct {
+		desc      string
+		delimiter string
+		lines     []string
+	}{
+		{"tab", "\t", []string{"!!Order!!\tCall\tName\tSect", "DL1ABC\tKlaus, Jr.\tB01"}},
+		{"tab with comma directive", "\t", []string{"!!Order!!,Call\tName\tSect", "DL1ABC\tKlaus, Jr.\tB01"}},
+		{"space", " ", []string{"!!Order!! Call Name Sect", "DL1ABC Klaus,_Jr. B01"}},
+		{"auto", "", []string{"!!Order!!,Call;Name;Sect", "DL1ABC;Klaus, Jr.;B01"}},
+		{"auto with semicolon directive", "", []string{"!!Order!!;Call;Name;Sect", "DL1ABC;Klaus, Jr.;B01"}},
+		{"semicolon", ";", []string{"!!Order!!;Call;Name;Sect", "DL1ABC;Klaus, Jr.;B01"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			parser := NewCallHistoryParserWithDelimiter(tc.delimiter)
+			_, ok := parser.ParseEntry(tc.lines[0])
+			require.False(t, ok)
+			assert.Equal(t, FieldSet{FieldCall, FieldUserName, "Sect"}, parser.fieldSet)
+
+			entry, ok := parser.ParseEntry(tc.lines[1])
+			require.True(t, ok)
+			assert.Equal(t, "DL1ABC", entry.Key())
+			assert.Equal(t, "B01", entry.Get("Sect"))
+			assert.Contains(t, entry.Get(FieldUserName), "Klaus,")
+		})
+	}
+}
+
 func TestLoadCallHistoryFromFile(t *testing.T) {
 	tt := []struct {
 		filename        string
@@ -92,3 +123,107 @@ func TestLoadCallHistoryFromFile(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteCallHistory(t *testing.T) {
+	tt := []struct {
+		desc     string
+		database func() *Database
+		expected string
+	}{
+		{
+			desc: "individual field set",
+			database: func() *Database {
+				database := NewDatabase(FieldCall, FieldUserName, FieldIgnore, "Sect")
+				database.Add("DL3NEY", "DL3NEY", "Florian", "ignored content", "B36")
+				database.Add("DL1ABC", "DL1ABC", "Klaus", "", "B01")
+				return database
+			},
+			expected: "!!Order!!,Call,Name,,Sect\nDL1ABC,Klaus,,B01\nDL3NEY,Florian,,B36\n",
+		},
+		{
+			desc: "field constants",
+			database: func() *Database {
+				database := NewDatabase(FieldCall, FieldDXCC, FieldState, FieldCQZone, FieldITUZone, FieldGrid, FieldCheck, FieldSection, FieldOpName, FieldUserText)
+				database.Add("DL3NEY", "DL3NEY", "230", "", "14", "28", "JO62qm", "", "", "Florian", "worked on 80m - QSL via bureau!")
+				database.Add("W1AW", "W1AW", "291", "CT", "5", "8", "FN31", "37", "CT", "Hiram", "")
+				return database
+			},
+			expected: "!!Order!!,Call,DXCC,State,CQZone,ITUZone,Grid,CK,Sect,Name,UserText\nDL3NEY,230,,14,28,JO62qm,,,Florian,worked on 80m - QSL via bureau!\nW1AW,291,CT,5,8,FN31,37,CT,Hiram,\n",
+		},
+		{
+			desc: "no call field",
+			database: func() *Database {
+				database := NewDatabase()
+				database.Add("DL3NEY")
+				return database
+			},
+			expected: "!!Order!!,Call\nDL3NEY\n",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			database := tc.database()
+			buffer := new(bytes.Buffer)
+
+			err := WriteCallHistory(buffer, database)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, buffer.String())
+
+			readDatabase, err := ReadCallHistory(buffer)
+			require.NoError(t, err)
+			buffer.Reset()
+			err = WriteCallHistory(buffer, readDatabase)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, buffer.String(), "round trip")
+		})
+	}
+}
+
+func TestWriteCallHistory_Delimiters(t *testing.T) {
+	tt := []struct {
+		desc  string
+		value string
+	}{
+		{desc: "comma", value: "club station, Newington"},
+		{desc: "semicolon", value: "a;b"},
+		{desc: "line break", value: "a\nb"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			database := NewDatabase(FieldCall, FieldUserText)
+			database.Add("DL1ABC", "DL1ABC", tc.value)
+			buffer := new(bytes.Buffer)
+
+			err := WriteCallHistory(buffer, database)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWriteCallHistory_RoundTrip(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName, FieldUserText)
+	database.Add("W1AW", "W1AW", "Hiram", "club station - Newington")
+	database.Add("DL1ABC", "DL1ABC", "Klaus", "")
+	buffer := new(bytes.Buffer)
+	require.NoError(t, WriteCallHistory(buffer, database))
+
+	readDatabase, err := ReadCallHistory(buffer)
+	require.NoError(t, err)
+
+	for _, key := range []string{"W1AW", "DL1ABC"} {
+		expected, err := database.Find(key)
+		require.NoError(t, err)
+		actual, err := readDatabase.Find(key)
+		require.NoError(t, err)
+		require.NotEmpty(t, actual, key)
+		assert.Equal(t, key, actual[0].Key())
+		assert.Equal(t, expected[0].Get(FieldUserName), actual[0].Get(FieldUserName), key)
+		assert.Equal(t, expected[0].Get(FieldUserText), actual[0].Get(FieldUserText), key)
+	}
+}
+
+func TestReadCallHistory_LineTooLong(t *testing.T) {
+	database, err := ReadCallHistory(strings.NewReader("!!Order!!,Call,Name\nDL3NEY," + strings.Repeat("x", DefaultMaxLineLength) + "\n"))
+	assert.Error(t, err)
+	assert.Nil(t, database)
+}
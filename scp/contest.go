@@ -0,0 +1,73 @@
+package scp
+
+import (
+	"io"
+	"strings"
+	"unicode"
+)
+
+// SweepstakesFieldSet defines the fields of a master file for the ARRL Sweepstakes: the operator's name,
+// the check, and the section.
+var SweepstakesFieldSet = FieldSet{FieldCall, FieldOpName, FieldCheck, FieldSection}
+
+// ReadSweepstakes creates a new Database and fills it from a master file for the ARRL Sweepstakes that is read with
+// the given reader. The file uses the ASCII format of TR Log's TRMASTER file, e.g. "K1ABC =NJohn =C85 =SCT"
+// (see TRMasterFieldSet). Only the tags of the fields in SweepstakesFieldSet are kept, all other tags are ignored.
+func ReadSweepstakes(r io.Reader) (*Database, error) {
+	return readTRMaster(r, SweepstakesFieldSet)
+}
+
+// FieldDayFieldSet defines the fields of a master file for the ARRL Field Day: the class and the section.
+var FieldDayFieldSet = FieldSet{FieldCall, FieldClass, FieldSection}
+
+// ReadFieldDay creates a new Database and fills it from a master file for the ARRL Field Day that is read with
+// the given reader. The file uses the call history format, each line contains the callsign, the class, and the
+// section, e.g. "W1AW,2A,CT". If the file contains an !!Order!! directive, the field set of the directive is used
+// instead of FieldDayFieldSet.
+func ReadFieldDay(r io.Reader) (*Database, error) {
+	parser := newCallHistoryParser(FieldDayFieldSet, "")
+	result, err := Read(r, parser)
+	if err != nil {
+		return nil, err
+	}
+	result.fieldSet = parser.fieldSet
+	return result, nil
+}
+
+// VHFFieldSet defines the fields of a master file for VHF and UHF contests: the grid square.
+var VHFFieldSet = FieldSet{FieldCall, FieldGrid}
+
+// ReadVHF creates a new Database and fills it from a master file for VHF and UHF contests that is read with
+// the given reader. Each line contains the callsign, followed by the grid square, separated by whitespace, commas,
+// or semicolons, e.g. "W1AW FN31". If a line contains more than one valid grid square, the first one is used.
+// Values that are not a valid grid square (see ValidGrid) are ignored. Lines that begin with # or ; are comments.
+func ReadVHF(r io.Reader) (*Database, error) {
+	result, err := Read(r, VHFFormat)
+	if err != nil {
+		return nil, err
+	}
+	result.fieldSet = VHFFieldSet
+	return result, nil
+}
+
+// VHFFormat parses the entries of a master file for VHF and UHF contests (see ReadVHF).
+var VHFFormat = EntryParserFunc(func(line string) (Entry, bool) {
+	if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+		return Entry{}, false
+	}
+	words := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	if len(words) == 0 {
+		return Entry{}, false
+	}
+
+	fieldValues := make(FieldValues)
+	for _, word := range words[1:] {
+		if ValidGrid(word) {
+			fieldValues[FieldGrid] = word
+			break
+		}
+	}
+	return newEntry(words[0], fieldValues), true
+})
@@ -0,0 +1,98 @@
+package scp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSweepstakes(t *testing.T) {
+	const testSweepstakes = `; ARRL Sweepstakes
+K1ABC =NJohn =C85 =SEMA =K5
+W8ABC =sOH =c02
+N5XYZ =NBob =GEM10
+W1AW`
+
+	database, err := ReadSweepstakes(strings.NewReader(testSweepstakes))
+	require.NoError(t, err)
+	assert.Equal(t, SweepstakesFieldSet, database.FieldSet())
+	assert.Equal(t, 4, database.Len())
+
+	tt := []struct {
+		key      string
+		expected FieldValues
+	}{
+		{"K1ABC", FieldValues{FieldOpName: "John", FieldCheck: "85", FieldSection: "EMA"}},
+		{"W8ABC", FieldValues{FieldCheck: "02", FieldSection: "OH"}},
+		{"N5XYZ", FieldValues{FieldOpName: "Bob"}},
+		{"W1AW", FieldValues{}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.key, func(t *testing.T) {
+			actual, ok := database.Get(tc.key)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestReadFieldDay(t *testing.T) {
+	tt := []struct {
+		desc             string
+		input            string
+		expectedFieldSet FieldSet
+		expected         FieldValues
+	}{
+		{"default field set", "# ARRL Field Day\nW1AW,2A,CT\nK1ABC,1D,EMA\n", FieldDayFieldSet, FieldValues{FieldClass: "2A", FieldSection: "CT"}},
+		{"semicolons", "W1AW;2A;CT\nK1ABC;1D;EMA\n", FieldDayFieldSet, FieldValues{FieldClass: "2A", FieldSection: "CT"}},
+		{"order directive", "!!Order!!,Call,Sect,Class\nW1AW,CT,2A\nK1ABC,EMA,1D\n", FieldSet{FieldCall, FieldSection, FieldClass}, FieldValues{FieldClass: "2A", FieldSection: "CT"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			database, err := ReadFieldDay(strings.NewReader(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedFieldSet, database.FieldSet())
+			assert.Equal(t, 2, database.Len())
+
+			actual, ok := database.Get("W1AW")
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestReadVHF(t *testing.T) {
+	const testVHF = `# VHF contest
+W1AW FN31
+K1ABC,FN42ab
+N5XYZ;EM10;EM11
+W8ABC	EN91 rover
+DL3NEY QTH
+W9XYZ`
+
+	database, err := ReadVHF(strings.NewReader(testVHF))
+	require.NoError(t, err)
+	assert.Equal(t, VHFFieldSet, database.FieldSet())
+	assert.Equal(t, 6, database.Len())
+
+	tt := []struct {
+		key      string
+		expected FieldValues
+	}{
+		{"W1AW", FieldValues{FieldGrid: "FN31"}},
+		{"K1ABC", FieldValues{FieldGrid: "FN42ab"}},
+		{"N5XYZ", FieldValues{FieldGrid: "EM10"}},
+		{"W8ABC", FieldValues{FieldGrid: "EN91"}},
+		{"DL3NEY", FieldValues{}},
+		{"W9XYZ", FieldValues{}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.key, func(t *testing.T) {
+			actual, ok := database.Get(tc.key)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
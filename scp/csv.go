@@ -0,0 +1,101 @@
+package scp
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadCSV creates a new Database and fills it from the CSV data that is read with the given reader. The first row must
+// contain the column names, which define the field set of the database. Column names are mapped case-insensitively to
+// the known field names, e.g. "name" to Name. The column Call (or Callsign) contains the key of each entry. Columns with
+// unknown names are kept as custom fields.
+func ReadCSV(r io.Reader) (*Database, error) {
+	in := csv.NewReader(r)
+	in.TrimLeadingSpace = true
+	header, err := in.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the CSV data has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fieldSet := make(FieldSet, len(header))
+	for i, column := range header {
+		fieldSet[i] = csvFieldName(column)
+	}
+	callIndex := fieldSet.CallIndex()
+	if callIndex < 0 {
+		return nil, fmt.Errorf("the CSV data has no %s column", FieldCall)
+	}
+
+	database := NewDatabase(fieldSet...)
+	for {
+		record, err := in.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		database.Add(record[callIndex], record...)
+	}
+	return database, nil
+}
+
+var knownCSVFieldNames = func() map[string]FieldName {
+	result := map[string]FieldName{
+		"callsign": FieldCall,
+	}
+	for _, fieldSet := range []FieldSet{DefaultFieldSet, ADIFFieldSet, TRMasterFieldSet} {
+		for _, fieldName := range fieldSet {
+			result[strings.ToLower(string(fieldName))] = fieldName
+		}
+	}
+	return result
+}()
+
+func csvFieldName(column string) FieldName {
+	column = strings.TrimSpace(column)
+	if fieldName, ok := knownCSVFieldNames[strings.ToLower(column)]; ok {
+		return fieldName
+	}
+	return FieldName(column)
+}
+
+// WriteCSV writes the database to a writer in CSV format. The first row contains the column names: Call, followed by the
+// usable field names of the database's field set. Each following row contains the key of one entry and its field values.
+// The entries are written in alphabetical order.
+func WriteCSV(w io.Writer, d *Database) error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	fieldNames := d.fieldSet.UsableNames()
+	out := csv.NewWriter(w)
+
+	row := make([]string, len(fieldNames)+1)
+	row[0] = string(FieldCall)
+	for i, fieldName := range fieldNames {
+		row[i+1] = string(fieldName)
+	}
+	err := out.Write(row)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range d.entries() {
+		row[0] = entry.key
+		for i, fieldName := range fieldNames {
+			row[i+1] = entry.Get(fieldName)
+		}
+		err := out.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
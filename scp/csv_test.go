@@ -0,0 +1,64 @@
+package scp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName, FieldIgnore, FieldUserText)
+	database.Add("DL3NEY", "DL3NEY", "Florian", "ignored", "Hello, Contest Developer")
+	database.Add("DL1ABC", "DL1ABC", "Klaus", "ignored", "")
+
+	buffer := new(bytes.Buffer)
+	err := WriteCSV(buffer, database)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Call,Name,UserText\nDL1ABC,Klaus,\nDL3NEY,Florian,\"Hello, Contest Developer\"\n", buffer.String())
+}
+
+func TestReadCSV(t *testing.T) {
+	const testCSV = `callsign, name, Exch1, Rig
+DL3NEY, Florian, B36, "FT-1000, modified"
+DL1ABC, Klaus, B01,
+`
+	database, err := ReadCSV(strings.NewReader(testCSV))
+	require.NoError(t, err)
+	assert.Equal(t, FieldSet{FieldCall, FieldUserName, "Exch1", "Rig"}, database.FieldSet())
+
+	entries := database.entries()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "DL1ABC", entries[0].Key())
+	assert.Equal(t, []string{"Klaus", "B01", ""}, entries[0].GetValues(FieldUserName, "Exch1", "Rig"))
+	assert.Equal(t, "DL3NEY", entries[1].Key())
+	assert.Equal(t, []string{"Florian", "B36", "FT-1000, modified"}, entries[1].GetValues(FieldUserName, "Exch1", "Rig"))
+}
+
+func TestReadCSV_RoundTrip(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName, FieldUserText)
+	database.Add("DL3NEY", "DL3NEY", "Florian", "Hello, Contest Developer")
+
+	buffer := new(bytes.Buffer)
+	err := WriteCSV(buffer, database)
+	require.NoError(t, err)
+	readDatabase, err := ReadCSV(buffer)
+	require.NoError(t, err)
+
+	assert.Equal(t, database.FieldSet(), readDatabase.FieldSet())
+	assert.Equal(t, database.entries(), readDatabase.entries())
+}
+
+func TestReadCSV_Invalid(t *testing.T) {
+	_, err := ReadCSV(strings.NewReader(""))
+	assert.Error(t, err, "empty")
+
+	_, err = ReadCSV(strings.NewReader("Name,Sect\nFlorian,B36\n"))
+	assert.Error(t, err, "no call column")
+
+	_, err = ReadCSV(strings.NewReader("Call,Name\nDL3NEY\n"))
+	assert.Error(t, err, "wrong number of fields")
+}
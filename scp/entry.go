@@ -1,7 +1,10 @@
 package scp
 
 import (
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/texttheater/golang-levenshtein/levenshtein"
 )
@@ -48,6 +51,13 @@ func (e Entry) Get(field FieldName) string {
 	return e.fieldValues[field]
 }
 
+// Field returns the value of the field with the given name, or false if the field is not populated.
+// Since Match embeds Entry, this also provides the field values of a matching entry.
+func (e Entry) Field(field FieldName) (string, bool) {
+	value, ok := e.fieldValues[field]
+	return value, ok
+}
+
 // GetValues returns the values of the fields with the given names as slice.
 // The returned slice is of the same size as the number of field names. If a field
 // is not populated, the corresponding slice entry is empty.
@@ -59,6 +69,18 @@ func (e Entry) GetValues(fields ...FieldName) []string {
 	return result
 }
 
+// weight returns the numerical value of the given field, or 0 if the field does not contain a number.
+func (e Entry) weight(field FieldName) float64 {
+	if field == FieldIgnore {
+		return 0
+	}
+	result, err := strconv.ParseFloat(e.Get(field), 64)
+	if err != nil {
+		return 0
+	}
+	return result
+}
+
 // PopulatedFields returns a FieldSet that contains all populated fields of this Entry.
 func (e Entry) PopulatedFields() FieldSet {
 	result := make(FieldSet, 0, len(e.fieldValues))
@@ -68,11 +90,46 @@ func (e Entry) PopulatedFields() FieldSet {
 	return result
 }
 
-var levenshteinOptions = levenshtein.Options{
-	InsCost: 2,
-	DelCost: 100,
-	SubCost: 2,
-	Matches: levenshtein.IdenticalRunes,
+// EditCosts defines the costs of the editing operations that transform the input of a search into the key of an entry.
+// The higher the cost of an operation, the less similar are the input and the key if the operation is required.
+type EditCosts struct {
+	Insert     int
+	Delete     int
+	Substitute int
+	// Transpose is the cost of swapping two adjacent characters. If it is zero, transpositions are not considered
+	// and two swapped characters count as two substitutions.
+	Transpose int
+}
+
+// DefaultEditCosts are the costs that are used by Entry.EditTo and the EditDistanceScorer. Characters of the input
+// that are missing in the key are penalized heavily.
+var DefaultEditCosts = EditCosts{
+	Insert:     2,
+	Delete:     100,
+	Substitute: 2,
+}
+
+func (c EditCosts) levenshteinOptions() levenshtein.Options {
+	return levenshtein.Options{
+		InsCost: positiveOrDefault(c.Insert, DefaultEditCosts.Insert),
+		DelCost: positiveOrDefault(c.Delete, DefaultEditCosts.Delete),
+		SubCost: positiveOrDefault(c.Substitute, DefaultEditCosts.Substitute),
+		Matches: foldedRunes,
+	}
+}
+
+func positiveOrDefault(value, defaultValue int) int {
+	if value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+var levenshteinOptions = DefaultEditCosts.levenshteinOptions()
+
+// foldedRunes compares two runes ignoring the case and diacritical marks (see foldRune), e.g. 'Ü' matches 'U'.
+func foldedRunes(a, b rune) bool {
+	return a == b || foldRune(unicode.ToUpper(a)) == foldRune(unicode.ToUpper(b))
 }
 
 // CompareTo compares this Entry's key with the key of the given Entry. It returns a measure
@@ -84,9 +141,114 @@ func (e Entry) CompareTo(o Entry) (distance, accuracy) {
 
 // EditTo provides the editing distance, matching accuracy, and the given Entry's key as MatchingAssembly
 func (e Entry) EditTo(o Entry) (distance, accuracy, MatchingAssembly) {
-	matrix := levenshtein.MatrixForStrings([]rune(e.key), []rune(o.key), levenshteinOptions)
-	script := levenshtein.EditScriptForMatrix(matrix, levenshteinOptions)
-	matchingAssembly := newMatchingAssembly(e.key, o.key, script)
+	return editTo(e.key, o.key)
+}
+
+// Scorer computes the similarity between the input of a search and the key of an entry in the database.
+// The accuracy is between 0 and 1, the higher the accuracy, the closer the key matches the input.
+// The MatchingAssembly describes how the input is transformed into the key.
+type Scorer interface {
+	Score(input, key string) (distance int, accuracy float64, assembly MatchingAssembly)
+}
+
+// ScorerFunc wraps a function with the signature of Scorer.Score.
+type ScorerFunc func(input, key string) (int, float64, MatchingAssembly)
+
+func (f ScorerFunc) Score(input, key string) (int, float64, MatchingAssembly) {
+	return f(input, key)
+}
+
+// EditDistanceScorer is the default Scorer. It uses the editing distance to compute the similarity (see Entry.EditTo).
+var EditDistanceScorer = ScorerFunc(func(input, key string) (int, float64, MatchingAssembly) {
+	d, a, m := editTo(input, key)
+	return int(d), float64(a), m
+})
+
+// NewEditDistanceScorer returns a Scorer that uses the editing distance with the given costs to compute the similarity.
+// A cost of zero or less is replaced by the corresponding value of DefaultEditCosts, hence
+// NewEditDistanceScorer(DefaultEditCosts) behaves exactly like the EditDistanceScorer.
+func NewEditDistanceScorer(costs EditCosts) Scorer {
+	options := costs.levenshteinOptions()
+	if costs.Transpose <= 0 {
+		return ScorerFunc(func(input, key string) (int, float64, MatchingAssembly) {
+			d, a, m := editToWithOptions(input, key, options)
+			return int(d), float64(a), m
+		})
+	}
+	return ScorerFunc(func(input, key string) (int, float64, MatchingAssembly) {
+		return scoreWithDistance(input, key, options, func(source, target []rune) int {
+			return weightedDistance(source, target, options, uniformSubstituteCost(options.SubCost), costs.Transpose)
+		})
+	})
+}
+
+// TranspositionScorer uses the editing distance to compute the similarity like the EditDistanceScorer, but counts
+// two swapped adjacent characters as one substitution, e.g. "W1WA" is closer to "W1AW" than with the EditDistanceScorer.
+var TranspositionScorer = NewEditDistanceScorer(EditCosts{Transpose: DefaultEditCosts.Substitute})
+
+// scoreWithDistance scores the input against the key like the EditDistanceScorer with the given options, but uses the
+// given distance function instead, if it results in a smaller distance. The MatchingAssembly is always computed with
+// the given options.
+func scoreWithDistance(input, key string, options levenshtein.Options, dist func(source, target []rune) int) (int, float64, MatchingAssembly) {
+	d, a, m := editToWithOptions(input, key, options)
+	source, target := []rune(input), []rune(key)
+	if weighted := dist(source, target); weighted < int(d) {
+		sum := len(source) + len(target)
+		d = distance(weighted)
+		a = accuracy(float64(sum-weighted) / float64(sum))
+	}
+	return int(d), float64(a), m
+}
+
+func uniformSubstituteCost(cost int) func(a, b rune) int {
+	return func(rune, rune) int {
+		return cost
+	}
+}
+
+// weightedDistance computes the editing distance between source and target using the insert and delete costs of the
+// given options and the cost of each substitution that is returned by substituteCost. If transposeCost is greater than
+// zero, swapping two adjacent characters is a single operation with this cost (optimal string alignment distance).
+func weightedDistance(source, target []rune, options levenshtein.Options, substituteCost func(a, b rune) int, transposeCost int) int {
+	rows := make([][]int, len(source)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(target)+1)
+		rows[i][0] = i * options.DelCost
+	}
+	for j := range rows[0] {
+		rows[0][j] = j * options.InsCost
+	}
+	for i := 1; i <= len(source); i++ {
+		for j := 1; j <= len(target); j++ {
+			result := rows[i-1][j-1]
+			if !options.Matches(source[i-1], target[j-1]) {
+				result += substituteCost(source[i-1], target[j-1])
+			}
+			if deleted := rows[i-1][j] + options.DelCost; deleted < result {
+				result = deleted
+			}
+			if inserted := rows[i][j-1] + options.InsCost; inserted < result {
+				result = inserted
+			}
+			if transposeCost > 0 && i > 1 && j > 1 && options.Matches(source[i-1], target[j-2]) && options.Matches(source[i-2], target[j-1]) {
+				if transposed := rows[i-2][j-2] + transposeCost; transposed < result {
+					result = transposed
+				}
+			}
+			rows[i][j] = result
+		}
+	}
+	return rows[len(source)][len(target)]
+}
+
+func editTo(source, target string) (distance, accuracy, MatchingAssembly) {
+	return editToWithOptions(source, target, levenshteinOptions)
+}
+
+func editToWithOptions(source, target string, options levenshtein.Options) (distance, accuracy, MatchingAssembly) {
+	matrix := levenshtein.MatrixForStrings([]rune(source), []rune(target), options)
+	script := levenshtein.EditScriptForMatrix(matrix, options)
+	matchingAssembly := newMatchingAssembly(source, target, script)
 
 	sourcelength := len(matrix) - 1
 	targetlength := len(matrix[0]) - 1
@@ -129,7 +291,10 @@ type MatchingPart struct {
 // MatchingAssembly describes how a certain key matches to another key, using editing operations.
 type MatchingAssembly []MatchingPart
 
-func newMatchingAssembly(source, target string, script levenshtein.EditScript) MatchingAssembly {
+func newMatchingAssembly(sourceKey, targetKey string, script levenshtein.EditScript) MatchingAssembly {
+	// the edit script counts runes, not bytes
+	source := []rune(sourceKey)
+	target := []rune(targetKey)
 	rawScript := make(MatchingAssembly, 0, len(script))
 
 	lastPart := MatchingPart{NOP, ""}
@@ -139,7 +304,7 @@ func newMatchingAssembly(source, target string, script levenshtein.EditScript) M
 	for _, lop := range script {
 		switch lop {
 		case levenshtein.Match:
-			currentPart = MatchingPart{NOP, string(source[sourceIndex])}
+			currentPart = MatchingPart{NOP, string(target[targetIndex])}
 			sourceIndex++
 			targetIndex++
 		case levenshtein.Ins:
@@ -186,18 +351,20 @@ func newMatchingAssembly(source, target string, script levenshtein.EditScript) M
 			continue
 		}
 
-		lastLen := len(lastPart.Value)
-		currentLen := len(currentPart.Value)
+		lastValue := []rune(lastPart.Value)
+		currentValue := []rune(currentPart.Value)
+		lastLen := len(lastValue)
+		currentLen := len(currentValue)
 		var headValue string
 		var substitution, tail MatchingPart
 		if lastLen > currentLen {
 			headValue = currentPart.Value
-			substitution = MatchingPart{Substitute, lastPart.Value[:currentLen]}
-			tail = MatchingPart{Insert, lastPart.Value[currentLen:]}
+			substitution = MatchingPart{Substitute, string(lastValue[:currentLen])}
+			tail = MatchingPart{Insert, string(lastValue[currentLen:])}
 		} else if lastLen < currentLen {
-			headValue = currentPart.Value[:lastLen]
+			headValue = string(currentValue[:lastLen])
 			substitution = MatchingPart{Substitute, lastPart.Value}
-			tail = MatchingPart{Delete, currentPart.Value[lastLen:]}
+			tail = MatchingPart{Delete, string(currentValue[lastLen:])}
 		} else {
 			headValue = currentPart.Value
 			substitution = MatchingPart{Substitute, lastPart.Value}
@@ -231,8 +398,8 @@ func (m MatchingAssembly) LongestPart() int {
 		if e.OP != NOP {
 			continue
 		}
-		if result < len(e.Value) {
-			result = len(e.Value)
+		if length := utf8.RuneCountInString(e.Value); result < length {
+			result = length
 		}
 	}
 	return result
@@ -297,6 +464,12 @@ type EntryParser interface {
 	ParseEntry(string) (Entry, bool)
 }
 
+// CommentParser is an optional interface for an EntryParser. If the parser implements it, Read uses ParseComment
+// to recognize the comment lines and to extract their text (see Database.Comments).
+type CommentParser interface {
+	ParseComment(string) (string, bool)
+}
+
 // EntryParserFunc wraps a matching function into the EntryParser interface
 type EntryParserFunc func(string) (Entry, bool)
 
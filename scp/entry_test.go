@@ -55,6 +55,8 @@ func TestNewAnnotatedMatch(t *testing.T) {
 		{"aefgd", "abcd", MatchingAssembly{MatchingPart{NOP, "a"}, MatchingPart{Substitute, "bc"}, MatchingPart{Delete, "g"}, MatchingPart{NOP, "d"}}},
 		{"aady", "aaney", MatchingAssembly{MatchingPart{NOP, "aa"}, MatchingPart{Substitute, "n"}, MatchingPart{Insert, "e"}, MatchingPart{NOP, "y"}}},
 		{"aaney", "aady", MatchingAssembly{MatchingPart{NOP, "aa"}, MatchingPart{FalseFriend, "d"}, MatchingPart{Delete, "e"}, MatchingPart{NOP, "y"}}},
+		{"müller", "möller", MatchingAssembly{MatchingPart{NOP, "m"}, MatchingPart{Substitute, "ö"}, MatchingPart{NOP, "ller"}}},
+		{"åbc", "abcd", MatchingAssembly{MatchingPart{Substitute, "a"}, MatchingPart{NOP, "bc"}, MatchingPart{Insert, "d"}}},
 	}
 	for _, tc := range tt {
 		t.Run(fmt.Sprintf("%s -> %s", tc.input, tc.entry), func(t *testing.T) {
@@ -92,9 +94,115 @@ func TestPreferFalseFriends(t *testing.T) {
 	assert.Equal(t, MatchingAssembly{MatchingPart{NOP, "DL4"}, MatchingPart{Insert, "F"}, MatchingPart{NOP, "M"}}, m3, "third matching assembly")
 	assert.False(t, m3.ContainsFalseFriend(), "third entry contains no false friend")
 
-	match1 := Match{entry1, d1, a1, m1}
-	match2 := Match{entry2, d2, a2, m2}
-	match3 := Match{entry3, d3, a3, m3}
+	match1 := Match{Entry: entry1, distance: d1, accuracy: a1, Assembly: m1}
+	match2 := Match{Entry: entry2, distance: d2, accuracy: a2, Assembly: m2}
+	match3 := Match{Entry: entry3, distance: d3, accuracy: a3, Assembly: m3}
 	assert.True(t, match1.LessThan(match2), "match order 1")
 	assert.True(t, match1.LessThan(match3), "match order 2")
 }
+
+func TestEditTo_FoldedRunes(t *testing.T) {
+	tt := []struct {
+		input    string
+		entry    string
+		expected string
+	}{
+		{"MULLER", "MÜLLER", "[MÜLLER]"},
+		{"MÜLLER", "MULLER", "[MULLER]"},
+		{"SP9LODZ", "SP9ŁÓDŹ", "[SP9ŁÓDŹ]"},
+		{"ŁÓDŹ", "LODZA", "[LODZ]A"},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%s -> %s", tc.input, tc.entry), func(t *testing.T) {
+			_, accuracy, assembly := newEntry(tc.input, nil).EditTo(newEntry(tc.entry, nil))
+			match := Match{Entry: newEntry(tc.entry, nil), accuracy: accuracy, Assembly: assembly}
+			assert.Equal(t, tc.expected, match.Highlight())
+			assert.Equal(t, tc.entry, assembly.String())
+		})
+	}
+}
+
+func TestNewEditDistanceScorer(t *testing.T) {
+	pairs := [][2]string{
+		{"DL1ABC", "DL1ABC"},
+		{"DL1AB", "DL1ABC"},
+		{"DL1ABC", "DL1AB"},
+		{"DL1ABD", "DL1ABC"},
+		{"DL4M", "DL4W"},
+		{"W1WA", "W1AW"},
+	}
+	for _, pair := range pairs {
+		t.Run(fmt.Sprintf("%s -> %s", pair[0], pair[1]), func(t *testing.T) {
+			expectedDistance, expectedAccuracy, expectedAssembly := EditDistanceScorer.Score(pair[0], pair[1])
+			for _, costs := range []EditCosts{DefaultEditCosts, {}, {Insert: -1}} {
+				actualDistance, actualAccuracy, actualAssembly := NewEditDistanceScorer(costs).Score(pair[0], pair[1])
+				assert.Equal(t, expectedDistance, actualDistance, "distance %v", costs)
+				assert.Equal(t, expectedAccuracy, actualAccuracy, "accuracy %v", costs)
+				assert.Equal(t, expectedAssembly, actualAssembly, "assembly %v", costs)
+			}
+		})
+	}
+
+	_, defaultAccuracy, _ := EditDistanceScorer.Score("DL1ABCX", "DL1ABC")
+	_, cheapDeleteAccuracy, assembly := NewEditDistanceScorer(EditCosts{Delete: 2}).Score("DL1ABCX", "DL1ABC")
+	assert.Less(t, defaultAccuracy, 0.0)
+	assert.Greater(t, cheapDeleteAccuracy, DefaultAccuracyThreshold)
+	assert.Equal(t, "DL1ABC", assembly.String())
+
+	_, defaultAccuracy, _ = EditDistanceScorer.Score("DL1ABD", "DL1ABC")
+	_, cheapSubstituteAccuracy, _ := NewEditDistanceScorer(EditCosts{Substitute: 1}).Score("DL1ABD", "DL1ABC")
+	assert.Greater(t, cheapSubstituteAccuracy, defaultAccuracy)
+}
+
+func TestTranspositionScorer(t *testing.T) {
+	tt := []struct {
+		input      string
+		key        string
+		transposed bool
+	}{
+		{"W1WA", "W1AW", true},
+		{"1WAW", "W1AW", true},
+		{"DL3NYE", "DL3NEY", true},
+		{"LD1ABC", "DL1ABC", true},
+		{"DL1ABC", "DL1ABC", false},
+		{"DL1AB", "DL1ABC", false},
+		{"DL1ABD", "DL1ABC", false},
+		{"W1AX", "W1AW", false},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%s -> %s", tc.input, tc.key), func(t *testing.T) {
+			defaultDistance, defaultAccuracy, defaultAssembly := EditDistanceScorer.Score(tc.input, tc.key)
+			actualDistance, actualAccuracy, actualAssembly := TranspositionScorer.Score(tc.input, tc.key)
+			assert.Equal(t, defaultAssembly, actualAssembly)
+			if tc.transposed {
+				assert.Less(t, actualDistance, defaultDistance)
+				assert.Greater(t, actualAccuracy, defaultAccuracy)
+			} else {
+				assert.Equal(t, defaultDistance, actualDistance)
+				assert.Equal(t, defaultAccuracy, actualAccuracy)
+			}
+		})
+	}
+}
+
+func TestWeightedDistance(t *testing.T) {
+	tt := []struct {
+		source   string
+		target   string
+		expected int
+	}{
+		{"", "", 0},
+		{"AB", "BA", 2},
+		{"ABC", "ACB", 2},
+		{"ABC", "CBA", 4},
+		{"AB", "ABC", 2},
+		{"ABC", "AB", 100},
+		{"CA", "ABC", 6},
+		{"ÄB", "BA", 2},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%s -> %s", tc.source, tc.target), func(t *testing.T) {
+			assert.Equal(t, tc.expected, weightedDistance([]rune(tc.source), []rune(tc.target), levenshteinOptions, uniformSubstituteCost(2), 2))
+		})
+	}
+}
@@ -1,13 +1,41 @@
 package scp
 
 import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"os"
 	"os/user"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ftl/localcopy"
 )
 
+// ErrNotModified is returned by DownloadContext if the remote file was not modified since the local copy was stored.
+var ErrNotModified = errors.New("database not modified")
+
+// ErrOutdated is returned by OpenOrDownload together with the database from an outdated local copy, if the download of a
+// newer version failed. It is a warning, the returned database can still be used.
+var ErrOutdated = errors.New("using outdated local copy")
+
+// ErrChecksumMismatch is returned by DownloadContext if the downloaded file does not match the expected checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// defaultHTTPClient is used to download the database file if no other client is given. Like the client of
+// localcopy, it aborts requests that take longer than 10 seconds.
+var defaultHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
 // LoadLocal loads the database from a file in the local filesystem.
 func LoadLocal(localFilename string) (*Database, error) {
 	database, err := localcopy.LoadLocal(localFilename, func(r io.Reader) (interface{}, error) {
@@ -30,23 +58,679 @@ func LoadRemote(remoteURL string) (*Database, error) {
 	return database.(*Database), nil
 }
 
+// OpenOrDownload loads the database from the given local file. If the local file is missing or older than the given
+// maximum age, the database file is downloaded from the given remote URL first. The age of the local file is the time
+// since it was last checked against the remote file (see UpdateIfOlderThan). A maximum age <= 0 means that an existing
+// local file never expires, i.e. the network is only accessed if there is no local copy.
+//
+// If the download fails, but an outdated local copy exists, the database is loaded from the local copy and returned
+// together with an error that wraps ErrOutdated and describes the failed download.
+func OpenOrDownload(ctx context.Context, remoteURL, localFilename string, maxAge time.Duration, opts ...DownloadOption) (*Database, error) {
+	_, err := os.Stat(localFilename)
+	cached := err == nil
+	if cached && (maxAge <= 0 || checkedWithin(localFilename, maxAge)) {
+		return LoadLocal(localFilename)
+	}
+
+	_, downloadErr := Fetch(ctx, remoteURL, localFilename, opts...)
+	if downloadErr != nil && !cached {
+		return nil, downloadErr
+	}
+
+	database, err := LoadLocal(localFilename)
+	if err != nil {
+		return nil, err
+	}
+	if downloadErr != nil {
+		return database, fmt.Errorf("%w: %v", ErrOutdated, downloadErr)
+	}
+	return database, nil
+}
+
+// DownloadOption configures how the database file is downloaded.
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	client     *http.Client
+	progress   ProgressFunc
+	timeout    time.Duration
+	attempts   int
+	retryDelay time.Duration
+
+	checksum    string
+	checksumURL string
+
+	noBackup bool
+}
+
+func newDownloadOptions(opts []DownloadOption) downloadOptions {
+	result := downloadOptions{
+		client:   defaultHTTPClient,
+		attempts: 1,
+	}
+	for _, opt := range opts {
+		opt(&result)
+	}
+	return result
+}
+
+// WithHTTPClient uses the given HTTP client to download the database file instead of the default client, which
+// aborts each request after 10 seconds.
+// This allows to configure proxies, TLS settings, or timeouts.
+func WithHTTPClient(client *http.Client) DownloadOption {
+	return func(o *downloadOptions) {
+		if client != nil {
+			o.client = client
+		}
+	}
+}
+
+// WithTimeout limits the time the whole download may take, including all retries. If the timeout expires,
+// the download is aborted and context.DeadlineExceeded is returned. By default, only each single request is
+// limited to 10 seconds (see WithHTTPClient).
+func WithTimeout(timeout time.Duration) DownloadOption {
+	return func(o *downloadOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetries retries a failed download up to the given number of attempts in total. The delay between
+// two attempts starts with the given base delay and doubles with each attempt. If the server responds
+// with a Retry-After header, the delay given by the server is used instead.
+// Only network errors and server errors (5xx) are retried, client errors (4xx) fail immediately.
+func WithRetries(maxAttempts int, baseDelay time.Duration) DownloadOption {
+	return func(o *downloadOptions) {
+		if maxAttempts > 0 {
+			o.attempts = maxAttempts
+		}
+		o.retryDelay = baseDelay
+	}
+}
+
+// WithChecksum verifies the downloaded file against the given hex encoded SHA-256 checksum.
+// If the checksum does not match, an existing local copy is left untouched and ErrChecksumMismatch is returned.
+func WithChecksum(sha256sum string) DownloadOption {
+	return func(o *downloadOptions) {
+		o.checksum = strings.ToLower(strings.TrimSpace(sha256sum))
+	}
+}
+
+// WithChecksumURL verifies the downloaded file against the SHA-256 checksum that is downloaded from the given URL.
+// The remote file is expected in the format of sha256sum, i.e. the hex encoded checksum is the first word in the file.
+// If the checksum does not match, an existing local copy is left untouched and ErrChecksumMismatch is returned.
+func WithChecksumURL(checksumURL string) DownloadOption {
+	return func(o *downloadOptions) {
+		o.checksumURL = checksumURL
+	}
+}
+
+// WithoutBackup does not keep a backup of the previous local copy when it is replaced by the downloaded file.
+func WithoutBackup() DownloadOption {
+	return func(o *downloadOptions) {
+		o.noBackup = true
+	}
+}
+
+// ProgressFunc is called while the database file is downloaded. It receives the number of bytes
+// read so far and the total number of bytes, or -1 if the total size is unknown.
+type ProgressFunc func(bytesRead, totalBytes int64)
+
+// WithProgress reports the progress of the download to the given function.
+func WithProgress(progress ProgressFunc) DownloadOption {
+	return func(o *downloadOptions) {
+		o.progress = progress
+	}
+}
+
 // Download downloads the database file from a remote URL and stores it locally.
 func Download(remoteURL, localFilename string) error {
-	return localcopy.Download(remoteURL, localFilename, func(r io.Reader) (interface{}, error) {
-		return ReadSCP(r)
-	})
+	return DownloadContext(context.Background(), remoteURL, localFilename)
+}
+
+// DownloadContext downloads the database file from a remote URL and stores it locally.
+// If the given context is canceled, the transfer is aborted and the context's error is returned.
+//
+// If a local copy already exists, the request is conditional on the modification time of the local copy
+// and on the ETag that was stored along with the local copy in a sidecar file (see ETagFilename).
+// If the remote file was not modified since then, the local copy is left untouched and ErrNotModified is returned.
+func DownloadContext(ctx context.Context, remoteURL, localFilename string, opts ...DownloadOption) error {
+	result, err := Fetch(ctx, remoteURL, localFilename, opts...)
+	if err != nil {
+		return err
+	}
+	if result.FromCache {
+		return ErrNotModified
+	}
+	return nil
+}
+
+// DownloadResult describes the outcome of fetching the database file.
+type DownloadResult struct {
+	// BytesWritten is the size of the stored local copy. It is 0 if the local copy was kept.
+	BytesWritten int64
+	// LastModified is the modification time of the remote file as reported by the server. It is the zero time
+	// if the server did not report a modification time.
+	LastModified time.Time
+	// FromCache indicates that the remote file was not modified and the local copy was kept.
+	FromCache bool
+}
+
+// Fetch downloads the database file from a remote URL and stores it locally, like DownloadContext.
+// Instead of ErrNotModified, Fetch reports in the returned DownloadResult if the local copy was kept.
+func Fetch(ctx context.Context, remoteURL, localFilename string, opts ...DownloadOption) (DownloadResult, error) {
+	options := newDownloadOptions(opts)
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	partial := &partialDownload{filename: localFilename + ".part"}
+	defer os.Remove(partial.filename)
+
+	for attempt := 1; ; attempt++ {
+		result, err := download(ctx, remoteURL, localFilename, options, partial)
+		var retryable *retryableError
+		if !errors.As(err, &retryable) {
+			return result, err
+		}
+		if attempt >= options.attempts {
+			return DownloadResult{}, retryable.err
+		}
+
+		delay := retryable.retryAfter
+		if delay == 0 {
+			delay = options.retryDelay << (attempt - 1)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return DownloadResult{}, ctx.Err()
+		}
+	}
+}
+
+// DownloadTo downloads the database file from a remote URL and copies its content to the given writer.
+// It returns the number of bytes written. Gzip compressed content is decompressed transparently.
+// Of the download options, only WithHTTPClient, WithTimeout and WithProgress apply, since the content
+// is streamed to the writer.
+func DownloadTo(ctx context.Context, w io.Writer, remoteURL string, opts ...DownloadOption) (int64, error) {
+	options := newDownloadOptions(opts)
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, remoteURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	response, err := options.client.Do(request)
+	if err == nil {
+		defer response.Body.Close()
+	}
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to download database: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to download database: %s", response.Status)
+	}
+
+	var body io.Reader = response.Body
+	if options.progress != nil {
+		body = &progressReader{r: body, total: response.ContentLength, progress: options.progress}
+	}
+	content, err := decompress(body, response)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decompress database: %v", err)
+	}
+
+	written, err := io.Copy(w, content)
+	if ctx.Err() != nil {
+		return written, ctx.Err()
+	}
+	return written, err
+}
+
+// DownloadMirrors downloads the database file from the first of the given remote URLs that succeeds
+// and stores it locally. The URLs are tried in the given order. If all downloads fail, the error of the
+// last attempt is returned. ErrNotModified is returned as soon as one remote URL reports that the local
+// copy is up to date.
+func DownloadMirrors(ctx context.Context, remoteURLs []string, localFilename string, opts ...DownloadOption) error {
+	if len(remoteURLs) == 0 {
+		return fmt.Errorf("no remote URL given")
+	}
+	var err error
+	for _, remoteURL := range remoteURLs {
+		err = DownloadContext(ctx, remoteURL, localFilename, opts...)
+		if err == nil || errors.Is(err, ErrNotModified) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func download(ctx context.Context, remoteURL, localFilename string, options downloadOptions, partial *partialDownload) (DownloadResult, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, remoteURL, nil)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	if partial.size > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", partial.size))
+		if partial.validator != "" {
+			request.Header.Set("If-Range", partial.validator)
+		}
+	} else if localFileInfo, err := os.Stat(localFilename); err == nil {
+		request.Header.Set("If-Modified-Since", localFileInfo.ModTime().UTC().Format(http.TimeFormat))
+		if etag, err := os.ReadFile(ETagFilename(localFilename)); err == nil && len(etag) > 0 {
+			request.Header.Set("If-None-Match", string(etag))
+		}
+	}
+
+	response, err := options.client.Do(request)
+	if err == nil {
+		defer response.Body.Close()
+	}
+	if ctx.Err() != nil {
+		return DownloadResult{}, ctx.Err()
+	}
+	if err != nil {
+		return DownloadResult{}, &retryableError{err: fmt.Errorf("failed to download database: %v", err)}
+	}
+	lastModified, _ := http.ParseTime(response.Header.Get("Last-Modified"))
+	switch {
+	case response.StatusCode == http.StatusOK:
+		// the server sends the whole file, either it does not support ranges or this is the first attempt
+		partial.restart(response)
+	case response.StatusCode == http.StatusPartialContent && partial.resumes(response):
+	case response.StatusCode == http.StatusNotModified:
+		touchChecked(localFilename)
+		return DownloadResult{LastModified: lastModified, FromCache: true}, nil
+	case response.StatusCode == http.StatusPartialContent || response.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		partial.restart(nil)
+		return DownloadResult{}, &retryableError{err: fmt.Errorf("failed to resume download: %s", response.Status)}
+	case response.StatusCode >= 500:
+		return DownloadResult{}, &retryableError{
+			err:        fmt.Errorf("failed to download database: %s", response.Status),
+			retryAfter: parseRetryAfter(response.Header.Get("Retry-After")),
+		}
+	default:
+		return DownloadResult{}, fmt.Errorf("failed to download database: %s", response.Status)
+	}
+
+	var body io.Reader = response.Body
+	if options.progress != nil {
+		total := response.ContentLength
+		if total >= 0 {
+			total += partial.size
+		}
+		body = &progressReader{r: body, read: partial.size, total: total, progress: options.progress}
+	}
+
+	err = partial.append(ctx, body)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	checksum, err := expectedChecksum(ctx, options)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	partialFile, err := os.Open(partial.filename)
+	if err != nil {
+		return DownloadResult{}, fmt.Errorf("failed to open downloaded database: %v", err)
+	}
+	defer partialFile.Close()
+	content, err := decompress(partialFile, response)
+	if err != nil {
+		partial.restart(nil)
+		return DownloadResult{}, &retryableError{err: fmt.Errorf("failed to decompress database: %v", err)}
+	}
+
+	written, err := store(ctx, localFilename, content, checksum, !options.noBackup)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	// use the remote modification time for the local copy, this keeps the next conditional request independent from the local clock
+	if !lastModified.IsZero() {
+		os.Chtimes(localFilename, lastModified, lastModified)
+	}
+
+	if etag := response.Header.Get("ETag"); etag != "" {
+		err = os.WriteFile(ETagFilename(localFilename), []byte(etag), 0644)
+	} else {
+		err = os.Remove(ETagFilename(localFilename))
+		if os.IsNotExist(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return DownloadResult{}, fmt.Errorf("failed to store ETag: %v", err)
+	}
+	touchChecked(localFilename)
+
+	return DownloadResult{BytesWritten: written, LastModified: lastModified}, nil
+}
+
+// partialDownload keeps the raw content that was already received, so an interrupted download
+// can be resumed using a range request.
+type partialDownload struct {
+	filename  string
+	size      int64
+	validator string
+}
+
+// restart discards the content received so far. If a response is given, its ETag or Last-Modified
+// header is used to ensure that a later range request refers to the same version of the remote file.
+func (p *partialDownload) restart(response *http.Response) {
+	p.size = 0
+	p.validator = ""
+	if response == nil {
+		return
+	}
+	if etag := response.Header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		p.validator = etag
+	} else {
+		p.validator = response.Header.Get("Last-Modified")
+	}
+}
+
+// resumes indicates if the given partial content response continues the content received so far.
+func (p *partialDownload) resumes(response *http.Response) bool {
+	var start int64
+	_, err := fmt.Sscanf(response.Header.Get("Content-Range"), "bytes %d-", &start)
+	return err == nil && start == p.size
+}
+
+// append writes the content of the given reader to the partial file. If reading fails, the content
+// received so far is kept and a retryable error is returned.
+func (p *partialDownload) append(ctx context.Context, r io.Reader) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if p.size == 0 {
+		flags |= os.O_TRUNC
+	}
+	os.MkdirAll(filepath.Dir(p.filename), os.ModePerm)
+	file, err := os.OpenFile(p.filename, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open temporary file: %v", err)
+	}
+	defer file.Close()
+
+	n, err := io.Copy(file, r)
+	p.size += n
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return fmt.Errorf("failed to store database locally: %v", err)
+	} else if err != nil {
+		// anything else than a file error happened while reading the remote file
+		return &retryableError{err: fmt.Errorf("failed to download database: %v", err)}
+	}
+	return file.Close()
+}
+
+// decompress wraps the given body into a gzip reader if the response is gzip compressed, either
+// indicated by the Content-Encoding header or by the .gz suffix of the requested URL.
+// The stored local copy is always the decompressed plain text file.
+func decompress(body io.Reader, response *http.Response) (io.Reader, error) {
+	gzipped := response.Header.Get("Content-Encoding") == "gzip"
+	if response.Request != nil && response.Request.URL != nil {
+		gzipped = gzipped || strings.HasSuffix(response.Request.URL.Path, ".gz")
+	}
+	if !gzipped || response.Uncompressed {
+		return body, nil
+	}
+
+	// servers sometimes label plain text as gzip, so look at the content first
+	buffered := bufio.NewReader(body)
+	magic, _ := buffered.Peek(2)
+	if len(magic) < 2 || magic[0] != 0x1f || magic[1] != 0x8b {
+		return buffered, nil
+	}
+	return gzip.NewReader(buffered)
+}
+
+// expectedChecksum returns the checksum that is configured in the given options, or the empty string if no checksum is configured.
+func expectedChecksum(ctx context.Context, options downloadOptions) (string, error) {
+	if options.checksum != "" || options.checksumURL == "" {
+		return options.checksum, nil
+	}
+	return downloadChecksum(ctx, options)
+}
+
+func downloadChecksum(ctx context.Context, options downloadOptions) (string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, options.checksumURL, nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := options.client.Do(request)
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "", &retryableError{err: fmt.Errorf("failed to download checksum: %v", err)}
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download checksum: %s", response.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(response.Body, 1024))
+	if err != nil {
+		return "", &retryableError{err: fmt.Errorf("failed to download checksum: %v", err)}
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("the checksum file is empty")
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// Checksum returns the hex encoded SHA-256 checksum of the content read from the given reader.
+func Checksum(r io.Reader) (string, error) {
+	hash := sha256.New()
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// retryableError indicates a transient error, the download may be retried after the given delay.
+type retryableError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
+
+// ETagFilename returns the name of the sidecar file that holds the ETag of the given local copy.
+func ETagFilename(localFilename string) string {
+	return localFilename + ".etag"
+}
+
+// CheckedFilename returns the name of the sidecar file whose modification time is the time when the given local copy
+// was last checked successfully against the remote file. The modification time of the local copy itself is the
+// modification time of the remote file and cannot be used to decide if the local copy needs to be checked again.
+func CheckedFilename(localFilename string) string {
+	return localFilename + ".checked"
+}
+
+// touchChecked records the current time as the time of the last successful check of the given local copy.
+func touchChecked(localFilename string) {
+	now := time.Now()
+	err := os.Chtimes(CheckedFilename(localFilename), now, now)
+	if os.IsNotExist(err) {
+		os.WriteFile(CheckedFilename(localFilename), nil, 0644)
+	}
+}
+
+// checkedWithin returns true if the given local copy exists and was checked successfully against the remote file
+// within the given duration.
+func checkedWithin(localFilename string, maxAge time.Duration) bool {
+	if _, err := os.Stat(localFilename); err != nil {
+		return false
+	}
+	checkedFileInfo, err := os.Stat(CheckedFilename(localFilename))
+	if err != nil {
+		return false
+	}
+	return time.Since(checkedFileInfo.ModTime()) < maxAge
+}
+
+// store writes the content of the given reader atomically to the given local file. The content is written to a temporary
+// file in the same directory first, which replaces the local file only after the content was completely stored. If a
+// checksum is given, the content must match this checksum to replace the local file. If backup is true, the previous
+// local file is kept as backup (see BackupFilename).
+func store(ctx context.Context, localFilename string, r io.Reader, checksum string, backup bool) (int64, error) {
+	dir := filepath.Dir(localFilename)
+	os.MkdirAll(dir, os.ModePerm)
+	tempFile, err := os.CreateTemp(dir, filepath.Base(localFilename)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to open temporary file: %v", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	hash := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tempFile, hash), r)
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to store database locally: %v", err)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); checksum != "" && actual != checksum {
+		return 0, fmt.Errorf("%w: expected %s, but got %s", ErrChecksumMismatch, checksum, actual)
+	}
+
+	err = tempFile.Chmod(0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store database locally: %v", err)
+	}
+	err = tempFile.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to store database locally: %v", err)
+	}
+	if backup {
+		err = os.Rename(localFilename, BackupFilename(localFilename))
+		if err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to backup the local copy: %v", err)
+		}
+	}
+	err = os.Rename(tempFile.Name(), localFilename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to store database locally: %v", err)
+	}
+	return written, nil
+}
+
+// BackupFilename returns the name of the file that holds the backup of the given local copy.
+func BackupFilename(localFilename string) string {
+	return localFilename + ".bak"
+}
+
+// RestoreBackup replaces the given local copy with its backup, i.e. the local copy that was replaced by the last download.
+// The next update will download the remote file again.
+func RestoreBackup(localFilename string) error {
+	err := os.Rename(BackupFilename(localFilename), localFilename)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %v", err)
+	}
+	// the ETag belongs to the replaced file
+	err = os.Remove(ETagFilename(localFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
 
 // Update updates the local copy of the database file from the given remote URL,
 // but only if an update is needed.
 func Update(remoteURL, localFilename string) (bool, error) {
-	return localcopy.Update(remoteURL, localFilename, func(r io.Reader) (interface{}, error) {
-		return ReadSCP(r)
-	})
+	return UpdateContext(context.Background(), remoteURL, localFilename)
 }
 
-// LocalFilename returns the absolute path of the default local filename in the current user's home directory.
+// UpdateContext updates the local copy of the database file from the given remote URL,
+// but only if an update is needed. If the given context is canceled, the update is aborted
+// and the context's error is returned.
+func UpdateContext(ctx context.Context, remoteURL, localFilename string, opts ...DownloadOption) (bool, error) {
+	result, err := Fetch(ctx, remoteURL, localFilename, opts...)
+	if err != nil {
+		return false, err
+	}
+	return !result.FromCache, nil
+}
+
+// UpdateIfOlderThan updates the local copy of the database file from the given remote URL, but only if the local copy
+// is missing or older than the given maximum age. Otherwise the network is not accessed at all. The age of the local
+// copy is the time since it was last checked successfully against the remote file, either by a download or by
+// a response that the remote file was not modified (see CheckedFilename). The function returns true if the local
+// copy was actually updated.
+func UpdateIfOlderThan(ctx context.Context, remoteURL, localFilename string, maxAge time.Duration, opts ...DownloadOption) (bool, error) {
+	if checkedWithin(localFilename, maxAge) {
+		return false, nil
+	}
+	return UpdateContext(ctx, remoteURL, localFilename, opts...)
+}
+
+// progressReader counts the bytes read from the underlying reader and reports them to a ProgressFunc.
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.progress(r.read, r.total)
+	}
+	return n, err
+}
+
+// LocalFilename returns the absolute path of the default local filename. The location follows the XDG Base Directory
+// specification: if $XDG_CONFIG_HOME is set to an absolute path, the file is located in $XDG_CONFIG_HOME/hamradio,
+// otherwise the DefaultLocalFilename in the current user's home directory is used.
 func LocalFilename() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+		return filepath.Join(configHome, configFilename), nil
+	}
 	usr, err := user.Current()
 	if err != nil {
 		return "", err
@@ -0,0 +1,586 @@
+package scp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var serveMasterSCP = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	http.ServeFile(w, r, "testdata/MASTER.SCP")
+})
+
+func TestDownload(t *testing.T) {
+	testServer := httptest.NewServer(serveMasterSCP)
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	err := Download(testServer.URL, localFilename)
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	actual, err := os.ReadFile(localFilename)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestDownload_NotModified(t *testing.T) {
+	testServer := httptest.NewServer(serveMasterSCP)
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	err := Download(testServer.URL, localFilename)
+	require.NoError(t, err)
+	localFileInfo, err := os.Stat(localFilename)
+	require.NoError(t, err)
+
+	err = Download(testServer.URL, localFilename)
+	assert.ErrorIs(t, err, ErrNotModified)
+	updated, err := Update(testServer.URL, localFilename)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	unchangedFileInfo, err := os.Stat(localFilename)
+	require.NoError(t, err)
+	assert.Equal(t, localFileInfo.ModTime(), unchangedFileInfo.ModTime())
+}
+
+func TestFetch(t *testing.T) {
+	testServer := httptest.NewServer(serveMasterSCP)
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+	fileInfo, err := os.Stat("testdata/MASTER.SCP")
+	require.NoError(t, err)
+
+	result, err := Fetch(context.Background(), testServer.URL, localFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileInfo.Size(), result.BytesWritten)
+	assert.True(t, fileInfo.ModTime().Truncate(time.Second).Equal(result.LastModified))
+	assert.False(t, result.FromCache)
+
+	result, err = Fetch(context.Background(), testServer.URL, localFilename)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), result.BytesWritten)
+	assert.True(t, result.FromCache)
+}
+
+func TestDownload_ETag(t *testing.T) {
+	const etag = `"abc123"`
+	var requestedETag string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedETag = r.Header.Get("If-None-Match")
+		if requestedETag == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte("DL1ABC\n"))
+	}))
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	err := Download(testServer.URL, localFilename)
+	require.NoError(t, err)
+	assert.Equal(t, "", requestedETag)
+	storedETag, err := os.ReadFile(ETagFilename(localFilename))
+	require.NoError(t, err)
+	assert.Equal(t, etag, string(storedETag))
+
+	err = Download(testServer.URL, localFilename)
+	assert.ErrorIs(t, err, ErrNotModified)
+	assert.Equal(t, etag, requestedETag)
+}
+
+func TestDownloadContext_HTTPClient(t *testing.T) {
+	testServer := httptest.NewServer(serveMasterSCP)
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	var usedTransport bool
+	client := &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			usedTransport = true
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	}
+
+	err := DownloadContext(context.Background(), testServer.URL, localFilename, WithHTTPClient(client))
+	require.NoError(t, err)
+	assert.True(t, usedTransport)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestDownloadContext_Retries(t *testing.T) {
+	tt := []struct {
+		desc             string
+		failures         int
+		status           int
+		attempts         int
+		expectedRequests int
+		valid            bool
+	}{
+		{"no retries", 1, http.StatusServiceUnavailable, 1, 1, false},
+		{"recover from server errors", 2, http.StatusServiceUnavailable, 3, 3, true},
+		{"give up after max attempts", 3, http.StatusInternalServerError, 3, 3, false},
+		{"do not retry client errors", 1, http.StatusNotFound, 3, 1, false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var requests int
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tc.failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tc.status)
+					return
+				}
+				serveMasterSCP(w, r)
+			}))
+			defer testServer.Close()
+			localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+			err := DownloadContext(context.Background(), testServer.URL, localFilename, WithRetries(tc.attempts, time.Millisecond))
+
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+			assert.Equal(t, tc.expectedRequests, requests)
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("invalid"))
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120"))
+	assert.InDelta(t, 10*time.Second, parseRetryAfter(time.Now().Add(10*time.Second).UTC().Format(http.TimeFormat)), float64(2*time.Second))
+}
+
+func TestDownloadContext_Checksum(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	checksum, err := Checksum(file)
+	file.Close()
+	require.NoError(t, err)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/MASTER.SCP.sha256" {
+			fmt.Fprintf(w, "%s  MASTER.SCP\n", checksum)
+			return
+		}
+		serveMasterSCP(w, r)
+	}))
+	defer testServer.Close()
+
+	t.Run("matching checksum", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := DownloadContext(context.Background(), testServer.URL+"/MASTER.SCP", localFilename, WithChecksum(checksum))
+		assert.NoError(t, err)
+		assert.FileExists(t, localFilename)
+	})
+	t.Run("matching checksum from URL", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := DownloadContext(context.Background(), testServer.URL+"/MASTER.SCP", localFilename, WithChecksumURL(testServer.URL+"/MASTER.SCP.sha256"))
+		assert.NoError(t, err)
+		assert.FileExists(t, localFilename)
+	})
+	t.Run("mismatching checksum", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := os.WriteFile(localFilename, []byte("DL1ABC\n"), 0644)
+		require.NoError(t, err)
+		os.Chtimes(localFilename, time.Unix(0, 0), time.Unix(0, 0))
+
+		err = DownloadContext(context.Background(), testServer.URL+"/MASTER.SCP", localFilename, WithChecksum("abc"))
+		assert.ErrorIs(t, err, ErrChecksumMismatch)
+		content, err := os.ReadFile(localFilename)
+		require.NoError(t, err)
+		assert.Equal(t, "DL1ABC\n", string(content))
+	})
+}
+
+func TestDownloadContext_Atomic(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1000")
+		w.Write([]byte("DL1ABC\nDL2"))
+	}))
+	defer testServer.Close()
+	dir := t.TempDir()
+	localFilename := filepath.Join(dir, "MASTER.SCP")
+	err := os.WriteFile(localFilename, []byte("DL1ABC\n"), 0644)
+	require.NoError(t, err)
+	os.Chtimes(localFilename, time.Unix(0, 0), time.Unix(0, 0))
+
+	err = Download(testServer.URL, localFilename)
+	assert.Error(t, err)
+
+	content, err := os.ReadFile(localFilename)
+	require.NoError(t, err)
+	assert.Equal(t, "DL1ABC\n", string(content))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files must be removed")
+}
+
+func TestDownloadContext_Gzip(t *testing.T) {
+	expected, err := os.ReadFile("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	compressed := new(bytes.Buffer)
+	gzipWriter := gzip.NewWriter(compressed)
+	gzipWriter.Write(expected)
+	gzipWriter.Close()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/encoded" {
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+		w.Write(compressed.Bytes())
+	}))
+	defer testServer.Close()
+
+	for _, path := range []string{"/encoded", "/MASTER.SCP.gz"} {
+		t.Run(path, func(t *testing.T) {
+			localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+			client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+
+			err := DownloadContext(context.Background(), testServer.URL+path, localFilename, WithHTTPClient(client))
+			require.NoError(t, err)
+
+			actual, err := os.ReadFile(localFilename)
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestDownload_Backup(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"new"`)
+		w.Write([]byte("DL2ABC\n"))
+	}))
+	defer testServer.Close()
+
+	t.Run("backup and restore", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := os.WriteFile(localFilename, []byte("DL1ABC\n"), 0644)
+		require.NoError(t, err)
+
+		err = Download(testServer.URL, localFilename)
+		require.NoError(t, err)
+		backup, err := os.ReadFile(BackupFilename(localFilename))
+		require.NoError(t, err)
+		assert.Equal(t, "DL1ABC\n", string(backup))
+
+		err = RestoreBackup(localFilename)
+		require.NoError(t, err)
+		content, err := os.ReadFile(localFilename)
+		require.NoError(t, err)
+		assert.Equal(t, "DL1ABC\n", string(content))
+		assert.NoFileExists(t, BackupFilename(localFilename))
+		assert.NoFileExists(t, ETagFilename(localFilename))
+	})
+	t.Run("without backup", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := os.WriteFile(localFilename, []byte("DL1ABC\n"), 0644)
+		require.NoError(t, err)
+
+		err = DownloadContext(context.Background(), testServer.URL, localFilename, WithoutBackup())
+		require.NoError(t, err)
+		assert.NoFileExists(t, BackupFilename(localFilename))
+		assert.Error(t, RestoreBackup(localFilename))
+	})
+}
+
+func TestChecksum(t *testing.T) {
+	actual, err := Checksum(strings.NewReader("DL1ABC\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "1c7833a33106d332cf22ea6cb1535a3c46d645e4389f139cef9594664bd1cd39", actual)
+}
+
+func TestDownloadContext_Resume(t *testing.T) {
+	content, err := os.ReadFile("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	modTime := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tt := []struct {
+		desc           string
+		supportsRanges bool
+		expectedRanges []string
+	}{
+		{"resume", true, []string{"", "bytes=20-"}},
+		{"ranges not supported", false, []string{"", "bytes=20-"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			var ranges []string
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ranges = append(ranges, r.Header.Get("Range"))
+				if len(ranges) == 1 {
+					// interrupt the first transfer after 20 bytes
+					w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+					w.Header().Set("Last-Modified", modTime.Format(http.TimeFormat))
+					w.Write(content[:20])
+					return
+				}
+				if !tc.supportsRanges {
+					r.Header.Del("Range")
+				}
+				http.ServeContent(w, r, "MASTER.SCP", modTime, bytes.NewReader(content))
+			}))
+			defer testServer.Close()
+			localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+			err := DownloadContext(context.Background(), testServer.URL, localFilename, WithRetries(2, time.Millisecond))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedRanges, ranges)
+			actual, err := os.ReadFile(localFilename)
+			require.NoError(t, err)
+			assert.Equal(t, content, actual)
+			assert.NoFileExists(t, localFilename+".part")
+		})
+	}
+}
+
+func TestDownloadTo(t *testing.T) {
+	testServer := httptest.NewServer(serveMasterSCP)
+	defer testServer.Close()
+	expected, err := os.ReadFile("testdata/MASTER.SCP")
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	written, err := DownloadTo(context.Background(), buffer, testServer.URL)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(expected)), written)
+	assert.Equal(t, expected, buffer.Bytes())
+
+	notFoundServer := httptest.NewServer(http.NotFoundHandler())
+	defer notFoundServer.Close()
+	_, err = DownloadTo(context.Background(), buffer, notFoundServer.URL)
+	assert.Error(t, err)
+}
+
+func TestDownloadMirrors(t *testing.T) {
+	brokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer brokenServer.Close()
+	testServer := httptest.NewServer(serveMasterSCP)
+	defer testServer.Close()
+
+	t.Run("fallback", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := DownloadMirrors(context.Background(), []string{brokenServer.URL, testServer.URL}, localFilename)
+		assert.NoError(t, err)
+		assert.FileExists(t, localFilename)
+	})
+	t.Run("all fail", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := DownloadMirrors(context.Background(), []string{brokenServer.URL, brokenServer.URL}, localFilename)
+		assert.Error(t, err)
+		assert.NoFileExists(t, localFilename)
+	})
+	t.Run("no mirrors", func(t *testing.T) {
+		localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+		err := DownloadMirrors(context.Background(), nil, localFilename)
+		assert.Error(t, err)
+	})
+}
+
+func TestDownloadContext_Progress(t *testing.T) {
+	testServer := httptest.NewServer(serveMasterSCP)
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+	fileInfo, err := os.Stat("testdata/MASTER.SCP")
+	require.NoError(t, err)
+
+	var lastRead, lastTotal int64
+	err = DownloadContext(context.Background(), testServer.URL, localFilename, WithProgress(func(bytesRead, totalBytes int64) {
+		assert.Greater(t, bytesRead, lastRead)
+		lastRead, lastTotal = bytesRead, totalBytes
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, fileInfo.Size(), lastRead)
+	assert.Equal(t, fileInfo.Size(), lastTotal)
+}
+
+func TestUpdateIfOlderThan(t *testing.T) {
+	var requests int
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("DL1ABC\n"))
+	}))
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	updated, err := UpdateIfOlderThan(context.Background(), testServer.URL, localFilename, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, updated, "missing file")
+	assert.Equal(t, 1, requests)
+
+	updated, err = UpdateIfOlderThan(context.Background(), testServer.URL, localFilename, time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, updated, "fresh file")
+	assert.Equal(t, 1, requests)
+
+	os.Chtimes(CheckedFilename(localFilename), time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour))
+	updated, err = UpdateIfOlderThan(context.Background(), testServer.URL, localFilename, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, updated, "stale file")
+	assert.Equal(t, 2, requests)
+}
+
+func TestUpdateIfOlderThan_OldRemoteFile(t *testing.T) {
+	lastModified := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+	var requests int
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-Modified-Since") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		w.Write([]byte("DL1ABC\n"))
+	}))
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	updated, err := UpdateIfOlderThan(context.Background(), testServer.URL, localFilename, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, updated, "missing file")
+	assert.Equal(t, 1, requests)
+
+	updated, err = UpdateIfOlderThan(context.Background(), testServer.URL, localFilename, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, updated, "checked just now")
+	assert.Equal(t, 1, requests)
+
+	os.Chtimes(CheckedFilename(localFilename), time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour))
+	updated, err = UpdateIfOlderThan(context.Background(), testServer.URL, localFilename, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, updated, "not modified")
+	assert.Equal(t, 2, requests)
+
+	updated, err = UpdateIfOlderThan(context.Background(), testServer.URL, localFilename, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, updated, "checked by the not modified response")
+	assert.Equal(t, 2, requests)
+}
+
+func TestOpenOrDownload(t *testing.T) {
+	available := true
+	var requests int
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !available {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("DL1ABC\n"))
+	}))
+	defer testServer.Close()
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	database, err := OpenOrDownload(context.Background(), testServer.URL, localFilename, 0)
+	require.NoError(t, err, "missing cache")
+	assert.Equal(t, []string{"DL1ABC"}, mustFindStrings(t, database, "DL1ABC"))
+	assert.Equal(t, 1, requests)
+
+	available = false
+	database, err = OpenOrDownload(context.Background(), testServer.URL, localFilename, 0)
+	require.NoError(t, err, "existing cache")
+	assert.Equal(t, []string{"DL1ABC"}, mustFindStrings(t, database, "DL1ABC"))
+	assert.Equal(t, 1, requests)
+
+	os.Chtimes(CheckedFilename(localFilename), time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour))
+	database, err = OpenOrDownload(context.Background(), testServer.URL, localFilename, time.Hour)
+	assert.ErrorIs(t, err, ErrOutdated, "stale cache")
+	require.NotNil(t, database)
+	assert.Equal(t, []string{"DL1ABC"}, mustFindStrings(t, database, "DL1ABC"))
+	assert.Equal(t, 2, requests)
+
+	os.Remove(localFilename)
+	database, err = OpenOrDownload(context.Background(), testServer.URL, localFilename, time.Hour)
+	assert.Error(t, err, "no cache and no network")
+	assert.Nil(t, database)
+}
+
+func mustFindStrings(t *testing.T, database *Database, s string) []string {
+	t.Helper()
+	result, err := database.FindStrings(s)
+	require.NoError(t, err)
+	return result
+}
+
+func TestDownloadContext_Canceled(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("DL1ABC\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer testServer.Close()
+	defer close(release)
+	localFilename := filepath.Join(t.TempDir(), "MASTER.SCP")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := DownloadContext(ctx, testServer.URL, localFilename)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	start = time.Now()
+	err = DownloadContext(context.Background(), testServer.URL, localFilename, WithTimeout(50*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestLocalFilename(t *testing.T) {
+	usr, err := user.Current()
+	require.NoError(t, err)
+
+	tt := []struct {
+		desc          string
+		xdgConfigHome string
+		expected      string
+	}{
+		{"default", "", filepath.Join(usr.HomeDir, ".config", "hamradio", "MASTER.SCP")},
+		{"XDG_CONFIG_HOME", "/tmp/config", filepath.Join("/tmp/config", "hamradio", "MASTER.SCP")},
+		{"relative XDG_CONFIG_HOME", "config", filepath.Join(usr.HomeDir, ".config", "hamradio", "MASTER.SCP")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", tc.xdgConfigHome)
+
+			actual, err := LocalFilename()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
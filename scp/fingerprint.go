@@ -2,6 +2,8 @@ package scp
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 type fingerprint []byte
@@ -14,17 +16,71 @@ func (fp fingerprint) Equal(other fingerprint) bool {
 	return string(fp) == string(other)
 }
 
+// mask returns a bit mask with one bit set for each distinct character of this fingerprint. The characters of
+// a fingerprint are upper case letters and digits, which are mapped to distinct bits.
+func (fp fingerprint) mask() uint64 {
+	var result uint64
+	for _, b := range fp {
+		result |= 1 << (b % 64)
+	}
+	return result
+}
+
 func extractFingerprint(s string) fingerprint {
-	bytes := make([]byte, 0)
-	for _, b := range []byte(strings.ToUpper(s)) {
-		if !isCallsignChar(b) {
+	bytes := make([]byte, 0, len(s))
+	for _, r := range strings.ToUpper(s) {
+		r = foldRune(r)
+		if r >= utf8.RuneSelf || !isCallsignChar(byte(r)) {
 			continue
 		}
-		bytes = append(bytes, b)
+		bytes = append(bytes, byte(r))
 	}
 	return fingerprint(bytes)
 }
 
+// firstFingerprintByte returns the first byte of the fingerprint of the given key without computing
+// the whole fingerprint.
+func firstFingerprintByte(key string) (byte, bool) {
+	for _, r := range strings.ToUpper(key) {
+		r = foldRune(r)
+		if r < utf8.RuneSelf && isCallsignChar(byte(r)) {
+			return byte(r), true
+		}
+	}
+	return 0, false
+}
+
+// asciiFolding maps letters with diacritical marks to the corresponding ASCII letter.
+var asciiFolding = map[rune]rune{
+	'À': 'A', 'Á': 'A', 'Â': 'A', 'Ã': 'A', 'Ä': 'A', 'Å': 'A', 'Ą': 'A', 'Ă': 'A', 'Ā': 'A',
+	'Ç': 'C', 'Ć': 'C', 'Č': 'C',
+	'Ď': 'D', 'Đ': 'D',
+	'È': 'E', 'É': 'E', 'Ê': 'E', 'Ë': 'E', 'Ę': 'E', 'Ě': 'E', 'Ē': 'E',
+	'Ğ': 'G',
+	'Ì': 'I', 'Í': 'I', 'Î': 'I', 'Ï': 'I', 'İ': 'I', 'Ī': 'I',
+	'Ł': 'L', 'Ľ': 'L', 'Ĺ': 'L',
+	'Ñ': 'N', 'Ń': 'N', 'Ň': 'N',
+	'Ò': 'O', 'Ó': 'O', 'Ô': 'O', 'Õ': 'O', 'Ö': 'O', 'Ø': 'O', 'Ő': 'O', 'Ō': 'O',
+	'Ŕ': 'R', 'Ř': 'R',
+	'Ś': 'S', 'Š': 'S', 'Ş': 'S', 'ß': 'S',
+	'Ť': 'T', 'Ţ': 'T',
+	'Ù': 'U', 'Ú': 'U', 'Û': 'U', 'Ü': 'U', 'Ů': 'U', 'Ű': 'U', 'Ū': 'U',
+	'Ý': 'Y', 'Ÿ': 'Y',
+	'Ź': 'Z', 'Ż': 'Z', 'Ž': 'Z',
+}
+
+// foldRune returns the ASCII letter that corresponds to the given upper case letter with diacritical marks,
+// e.g. 'Ü' becomes 'U'. All other runes are returned unchanged.
+func foldRune(r rune) rune {
+	if r < utf8.RuneSelf {
+		return r
+	}
+	if folded, ok := asciiFolding[unicode.ToUpper(r)]; ok {
+		return folded
+	}
+	return r
+}
+
 func isCallsignChar(b byte) bool {
 	switch {
 	case b >= 'A' && b <= 'Z':
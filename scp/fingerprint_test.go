@@ -1,6 +1,7 @@
 package scp
 
 import (
+	"math/bits"
 	"testing"
 )
 
@@ -49,6 +50,9 @@ func TestExtractFingerprint(t *testing.T) {
 		{"F/DL1ABC/p", fingerprint{'F', 'D', 'L', '1', 'A', 'B', 'C', 'P'}},
 		{"EA7/DL1ABC/p", fingerprint{'E', 'A', '7', 'D', 'L', '1', 'A', 'B', 'C', 'P'}},
 		{"nmm", fingerprint{'N', 'M', 'M'}},
+		{"müller", fingerprint{'M', 'U', 'L', 'L', 'E', 'R'}},
+		{"SP9ŁÓDŹ", fingerprint{'S', 'P', '9', 'L', 'O', 'D', 'Z'}},
+		{"Я1ABC", fingerprint{'1', 'A', 'B', 'C'}},
 	}
 	for _, testCase := range testCases {
 		actual := extractFingerprint(testCase.value)
@@ -57,3 +61,22 @@ func TestExtractFingerprint(t *testing.T) {
 		}
 	}
 }
+
+func TestFingerprint_Mask(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected int
+	}{
+		{"", 0},
+		{"ABC", 3},
+		{"AAA", 1},
+		{"DL1ABC", 6},
+		{"ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789", 36},
+	}
+	for _, testCase := range testCases {
+		actual := bits.OnesCount64(extractFingerprint(testCase.value).mask())
+		if actual != testCase.expected {
+			t.Errorf("%q: expected %d distinct characters but got %d", testCase.value, testCase.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package scp
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// gobFormatVersion is incremented whenever the binary format written by Save changes incompatibly, including any
+// change of the fingerprints (see extractFingerprint).
+const gobFormatVersion = 2
+
+type gobEntry struct {
+	Key         string
+	Fingerprint []byte
+	Fields      FieldValues
+}
+
+type gobDatabase struct {
+	Version  int
+	FieldSet FieldSet
+	Comments []string
+	Entries  []gobEntry
+}
+
+// Save writes the database in a compact binary format (using encoding/gob) to the given writer. The entries are
+// stored with their precomputed fingerprints, so that Load can restore the database faster than parsing the
+// original file again. Only the field set, the comments, and the entries are stored, the configuration of the database
+// (e.g. the normalization or the accuracy threshold) is not stored and must be applied again after Load.
+func (d *Database) Save(w io.Writer) error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	entries := d.entries()
+	data := gobDatabase{
+		Version:  gobFormatVersion,
+		FieldSet: d.fieldSet,
+		Comments: d.comments,
+		Entries:  make([]gobEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		data.Entries[i] = gobEntry{
+			Key:         entry.key,
+			Fingerprint: entry.fingerprint,
+			Fields:      entry.fieldValues,
+		}
+	}
+	return gob.NewEncoder(w).Encode(data)
+}
+
+// Load reads a database that was written with Database.Save from the given reader. The stored fingerprint of each
+// entry is validated against its key, Load returns an error if the data is corrupt or was written with a different
+// way to compute the fingerprints.
+func Load(r io.Reader) (*Database, error) {
+	var data gobDatabase
+	err := gob.NewDecoder(r).Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+	if data.Version != gobFormatVersion {
+		return nil, fmt.Errorf("unsupported format version %d, expected %d", data.Version, gobFormatVersion)
+	}
+
+	result := NewDatabase(data.FieldSet...)
+	result.comments = data.Comments
+	for _, entry := range data.Entries {
+		if !extractFingerprint(entry.Key).Equal(entry.Fingerprint) {
+			return nil, fmt.Errorf("invalid fingerprint %q of entry %s", entry.Fingerprint, entry.Key)
+		}
+		result.add(Entry{
+			key:         entry.Key,
+			fingerprint: fingerprint(entry.Fingerprint),
+			fieldValues: entry.Fields,
+		})
+	}
+	return result, nil
+}
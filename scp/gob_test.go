@@ -0,0 +1,62 @@
+package scp
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	file, err := os.Open("testdata/DefaultFieldSet.callhistory")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := ReadCallHistory(file)
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	err = database.Save(buffer)
+	require.NoError(t, err)
+
+	loaded, err := Load(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, database.FieldSet(), loaded.FieldSet())
+	assert.Equal(t, database.Comments(), loaded.Comments())
+	assert.Equal(t, database.items, loaded.items)
+
+	expected, err := database.Find("DL1ABC")
+	require.NoError(t, err)
+	actual, err := loaded.Find("DL1ABC")
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	_, err := Load(bytes.NewBufferString("MASTER.SCP"))
+	assert.Error(t, err)
+
+	tt := []struct {
+		desc        string
+		fingerprint []byte
+	}{
+		{"empty fingerprint", nil},
+		{"inconsistent fingerprint", []byte("DL2ABC")},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			err := gob.NewEncoder(buffer).Encode(gobDatabase{
+				Version: gobFormatVersion,
+				Entries: []gobEntry{{Key: "DL1ABC", Fingerprint: tc.fingerprint}},
+			})
+			require.NoError(t, err)
+
+			database, err := Load(buffer)
+			assert.Error(t, err)
+			assert.Nil(t, database)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package scp
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// keyIndex provides access to the keys of the entries in a database in different orders. The index is built
+// on demand and invalidated whenever the database changes.
+type keyIndex struct {
+	lock     sync.Mutex
+	valid    bool
+	sorted   []string
+	phonetic map[string][]string
+}
+
+func (i *keyIndex) invalidate() {
+	if i == nil {
+		return
+	}
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.valid = false
+	i.sorted = nil
+	i.phonetic = nil
+}
+
+func (i *keyIndex) build(d *Database) {
+	if i.valid {
+		return
+	}
+	entries := d.entries()
+	i.sorted = make([]string, len(entries))
+	i.phonetic = make(map[string][]string)
+	for j, entry := range entries {
+		i.sorted[j] = entry.key
+		code := PhoneticCode(entry.key)
+		i.phonetic[code] = append(i.phonetic[code], entry.key)
+	}
+	i.valid = true
+}
+
+// sortedKeys returns all keys in alphabetical order.
+func (i *keyIndex) sortedKeys(d *Database) []string {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.build(d)
+	return i.sorted
+}
+
+// phoneticKeys returns all keys with the given phonetic code in alphabetical order.
+func (i *keyIndex) phoneticKeys(d *Database, code string) []string {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.build(d)
+	return i.phonetic[code]
+}
+
+// FindByPrefix returns the keys of all entries in database that begin with the given prefix, in alphabetical order.
+func (d *Database) FindByPrefix(prefix string) []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	prefix = strings.ToUpper(strings.TrimSpace(prefix))
+	if d.index == nil {
+		return []string{}
+	}
+
+	keys := d.index.sortedKeys(d)
+	start := sort.SearchStrings(keys, prefix)
+	end := start
+	for end < len(keys) && strings.HasPrefix(keys[end], prefix) {
+		end++
+	}
+
+	result := make([]string, end-start)
+	copy(result, keys[start:end])
+	return result
+}
+
+// FindBySuffix returns the keys of all entries in database that end with the given suffix, in alphabetical order.
+func (d *Database) FindBySuffix(suffix string) []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	suffix = strings.ToUpper(strings.TrimSpace(suffix))
+	result := make([]string, 0)
+	if d.index == nil {
+		return result
+	}
+
+	for _, key := range d.index.sortedKeys(d) {
+		if strings.HasSuffix(key, suffix) {
+			result = append(result, key)
+		}
+	}
+	return result
+}
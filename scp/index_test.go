@@ -0,0 +1,58 @@
+package scp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabase_FindByPrefix(t *testing.T) {
+	database := NewDatabase()
+	database.Add("VK2ABC")
+	database.Add("DL3NEY")
+	database.Add("VK3XYZ")
+	database.Add("VE3ABC")
+
+	tt := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"VK", []string{"VK2ABC", "VK3XYZ"}},
+		{"vk3", []string{"VK3XYZ"}},
+		{"V", []string{"VE3ABC", "VK2ABC", "VK3XYZ"}},
+		{"DL3NEY", []string{"DL3NEY"}},
+		{"ZL", []string{}},
+		{"", []string{"DL3NEY", "VE3ABC", "VK2ABC", "VK3XYZ"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.prefix, func(t *testing.T) {
+			assert.Equal(t, tc.expected, database.FindByPrefix(tc.prefix))
+		})
+	}
+
+	database.Add("VK4AAA")
+	assert.Equal(t, []string{"VK2ABC", "VK3XYZ", "VK4AAA"}, database.FindByPrefix("VK"), "index is updated")
+}
+
+func TestDatabase_FindBySuffix(t *testing.T) {
+	database := NewDatabase()
+	database.Add("VK2ABC")
+	database.Add("DL3NEY")
+	database.Add("VK3XYZ")
+	database.Add("VE3ABC")
+
+	tt := []struct {
+		suffix   string
+		expected []string
+	}{
+		{"ABC", []string{"VE3ABC", "VK2ABC"}},
+		{"xyz", []string{"VK3XYZ"}},
+		{"3NEY", []string{"DL3NEY"}},
+		{"QQQ", []string{}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.suffix, func(t *testing.T) {
+			assert.Equal(t, tc.expected, database.FindBySuffix(tc.suffix))
+		})
+	}
+}
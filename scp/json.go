@@ -0,0 +1,105 @@
+package scp
+
+import (
+	"encoding/json"
+)
+
+type jsonEntry struct {
+	Key    string      `json:"key"`
+	Fields FieldValues `json:"fields,omitempty"`
+}
+
+type jsonDatabase struct {
+	FieldSet FieldSet    `json:"fieldSet"`
+	Comments []string    `json:"comments,omitempty"`
+	Entries  []jsonEntry `json:"entries"`
+}
+
+type jsonMatchingPart struct {
+	OP    MatchingOperation `json:"op"`
+	Value string            `json:"value"`
+}
+
+type jsonMatch struct {
+	jsonEntry
+	Accuracy float64            `json:"accuracy"`
+	Distance int                `json:"distance"`
+	Assembly []jsonMatchingPart `json:"assembly"`
+}
+
+// MarshalJSON encodes the database as JSON object with its field set, its comments, and its entries
+// with their field values. The entries are in alphabetical order.
+func (d *Database) MarshalJSON() ([]byte, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	entries := d.entries()
+	result := jsonDatabase{
+		FieldSet: d.fieldSet,
+		Comments: d.comments,
+		Entries:  make([]jsonEntry, len(entries)),
+	}
+	for i, entry := range entries {
+		result.Entries[i] = jsonEntry{Key: entry.key, Fields: entry.fieldValues}
+	}
+	return json.Marshal(result)
+}
+
+// UnmarshalJSON decodes a database from the JSON representation created by MarshalJSON.
+// The current content of the database is replaced.
+func (d *Database) UnmarshalJSON(data []byte) error {
+	var decoded jsonDatabase
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.fieldSet = decoded.FieldSet
+	if d.fieldSet == nil {
+		d.fieldSet = FieldSet{}
+	}
+	d.comments = decoded.Comments
+	d.items = make(map[byte]entrySet)
+	d.index = new(keyIndex)
+	d.cache.invalidate()
+	for _, entry := range decoded.Entries {
+		d.add(newEntry(entry.Key, entry.Fields))
+	}
+	return nil
+}
+
+// MarshalJSON encodes the match as JSON object with the key and the field values of the matching entry,
+// the accuracy, the editing distance, and the matching assembly.
+func (m Match) MarshalJSON() ([]byte, error) {
+	result := jsonMatch{
+		jsonEntry: jsonEntry{Key: m.key, Fields: m.fieldValues},
+		Accuracy:  float64(m.accuracy),
+		Distance:  int(m.distance),
+		Assembly:  make([]jsonMatchingPart, len(m.Assembly)),
+	}
+	for i, part := range m.Assembly {
+		result.Assembly[i] = jsonMatchingPart{OP: part.OP, Value: part.Value}
+	}
+	return json.Marshal(result)
+}
+
+// UnmarshalJSON decodes a match from the JSON representation created by MarshalJSON.
+func (m *Match) UnmarshalJSON(data []byte) error {
+	var decoded jsonMatch
+	err := json.Unmarshal(data, &decoded)
+	if err != nil {
+		return err
+	}
+
+	m.Entry = newEntry(decoded.Key, decoded.Fields)
+	m.accuracy = accuracy(decoded.Accuracy)
+	m.distance = distance(decoded.Distance)
+	m.Assembly = make(MatchingAssembly, len(decoded.Assembly))
+	for i, part := range decoded.Assembly {
+		m.Assembly[i] = MatchingPart{OP: part.OP, Value: part.Value}
+	}
+	return nil
+}
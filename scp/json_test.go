@@ -0,0 +1,54 @@
+package scp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabase_JSON(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName)
+	database.Add("DL3NEY", "DL3NEY", "Florian")
+	database.Add("N1MM", "N1MM", "")
+
+	data, err := json.Marshal(database)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"fieldSet": ["Call", "Name"],
+		"entries": [
+			{"key": "DL3NEY", "fields": {"Name": "Florian"}},
+			{"key": "N1MM", "fields": {"Name": ""}}
+		]
+	}`, string(data))
+
+	var decoded Database
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, database.FieldSet(), decoded.FieldSet())
+	assert.Equal(t, database.items, decoded.items)
+}
+
+func TestMatch_JSON(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName)
+	database.Add("DL3NEY", "DL3NEY", "Florian")
+	matches, err := database.Find("DL3NE")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	data, err := json.Marshal(matches[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"key": "DL3NEY",
+		"fields": {"Name": "Florian"},
+		"accuracy": 0.8181818181818182,
+		"distance": 2,
+		"assembly": [{"op": 0, "value": "DL3NE"}, {"op": 1, "value": "Y"}]
+	}`, string(data))
+
+	var decoded Match
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, matches[0], decoded)
+}
@@ -0,0 +1,56 @@
+package scp
+
+import (
+	"math"
+	"unicode"
+)
+
+// keyboardRows contains the keys of a QWERTY keyboard that are used in callsigns, row by row from top to bottom.
+var keyboardRows = []string{"1234567890", "QWERTYUIOP", "ASDFGHJKL", "ZXCVBNM"}
+
+// keyboardRowOffsets contains the horizontal offset of each row of keyboardRows, in units of the width of one key.
+var keyboardRowOffsets = []float64{0, 0.5, 0.75, 1.25}
+
+type keyPosition struct {
+	x, y float64
+}
+
+var keyPositions = func() map[rune]keyPosition {
+	result := make(map[rune]keyPosition)
+	for row, keys := range keyboardRows {
+		for column, key := range keys {
+			result[key] = keyPosition{x: float64(column) + keyboardRowOffsets[row], y: float64(row)}
+		}
+	}
+	return result
+}()
+
+// adjacentKeys returns true if the keys of the given characters are next to each other on a QWERTY keyboard,
+// horizontally or diagonally, e.g. Q and W, Q and A, or Q and 2.
+func adjacentKeys(a, b rune) bool {
+	positionA, ok := keyPositions[unicode.ToUpper(a)]
+	if !ok {
+		return false
+	}
+	positionB, ok := keyPositions[unicode.ToUpper(b)]
+	if !ok {
+		return false
+	}
+	return math.Hypot(positionA.x-positionB.x, positionA.y-positionB.y) <= 1.5
+}
+
+// KeyboardScorer uses the editing distance to compute the similarity like the EditDistanceScorer, but a substitution
+// of characters whose keys are next to each other on a QWERTY keyboard costs only half as much as other substitutions,
+// e.g. "W1QW" is closer to "W1AW" than "W1PW". This improves the order of the matches for typing errors.
+var KeyboardScorer = ScorerFunc(func(input, key string) (int, float64, MatchingAssembly) {
+	return scoreWithDistance(input, key, levenshteinOptions, func(source, target []rune) int {
+		return weightedDistance(source, target, levenshteinOptions, keyboardSubstituteCost, 0)
+	})
+})
+
+func keyboardSubstituteCost(a, b rune) int {
+	if adjacentKeys(a, b) {
+		return levenshteinOptions.SubCost / 2
+	}
+	return levenshteinOptions.SubCost
+}
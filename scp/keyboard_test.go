@@ -0,0 +1,66 @@
+package scp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdjacentKeys(t *testing.T) {
+	tt := []struct {
+		a, b     rune
+		expected bool
+	}{
+		{'Q', 'W', true},
+		{'q', 'a', true},
+		{'Q', '2', true},
+		{'S', 'Z', true},
+		{'M', 'K', true},
+		{'Q', 'S', false},
+		{'Q', 'E', false},
+		{'A', 'X', false},
+		{'1', 'P', false},
+		{'Q', '/', false},
+		{'Ä', 'A', false},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%c %c", tc.a, tc.b), func(t *testing.T) {
+			assert.Equal(t, tc.expected, adjacentKeys(tc.a, tc.b))
+			assert.Equal(t, tc.expected, adjacentKeys(tc.b, tc.a))
+		})
+	}
+}
+
+func TestKeyboardScorer(t *testing.T) {
+	_, adjacentAccuracy, assembly := KeyboardScorer.Score("W1QW", "W1AW")
+	_, distantAccuracy, _ := KeyboardScorer.Score("W1PW", "W1AW")
+	_, defaultAccuracy, _ := EditDistanceScorer.Score("W1QW", "W1AW")
+	assert.Greater(t, adjacentAccuracy, distantAccuracy)
+	assert.Greater(t, adjacentAccuracy, defaultAccuracy)
+	assert.Equal(t, "W1AW", assembly.String())
+
+	for _, pair := range [][2]string{{"W1AW", "W1AW"}, {"W1A", "W1AW"}, {"W1PW", "W1AW"}, {"DL4M", "DL4W"}} {
+		expectedDistance, expectedAccuracy, _ := EditDistanceScorer.Score(pair[0], pair[1])
+		actualDistance, actualAccuracy, _ := KeyboardScorer.Score(pair[0], pair[1])
+		assert.Equal(t, expectedDistance, actualDistance, "%s -> %s", pair[0], pair[1])
+		assert.Equal(t, expectedAccuracy, actualAccuracy, "%s -> %s", pair[0], pair[1])
+	}
+}
+
+func TestDatabase_FindWithScorer_Keyboard(t *testing.T) {
+	database := NewDatabase()
+	database.Add("W1BW")
+	database.Add("W1XW")
+
+	actual, err := database.FindWithScorer("W1SW", EditDistanceScorer)
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Equal(t, []string{"W1BW", "W1XW"}, []string{actual[0].Key(), actual[1].Key()})
+
+	actual, err = database.FindWithScorer("W1SW", KeyboardScorer)
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+	assert.Equal(t, []string{"W1XW", "W1BW"}, []string{actual[0].Key(), actual[1].Key()})
+}
@@ -0,0 +1,123 @@
+package scp
+
+// MergePolicy defines how conflicting field values are handled when two databases are merged.
+type MergePolicy int
+
+const (
+	// KeepExisting keeps the field values of the receiving database if both databases contain a value for the same field.
+	KeepExisting MergePolicy = iota
+	// OverwriteExisting overwrites the field values of the receiving database with the values of the other database.
+	OverwriteExisting
+)
+
+// Merge adds all entries of the other database to this database. The field values of entries with the same key are
+// combined, values for the same field are handled according to the given policy. Fields of the other database that
+// are not contained in the field set of this database are appended to the field set.
+func (d *Database) Merge(other *Database, policy MergePolicy) {
+	if other == d {
+		return
+	}
+	other.lock.RLock()
+	otherFieldSet := other.fieldSet
+	otherEntries := other.entries()
+	other.lock.RUnlock()
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, fieldName := range otherFieldSet.UsableNames() {
+		if d.fieldSet.IndexOf(fieldName) == -1 {
+			d.fieldSet = append(d.fieldSet, fieldName)
+		}
+	}
+
+	for _, otherEntry := range otherEntries {
+		existing, ok := d.lookup(otherEntry.key)
+		if !ok {
+			d.add(otherEntry)
+			continue
+		}
+
+		fieldValues := make(FieldValues, len(existing.fieldValues)+len(otherEntry.fieldValues))
+		for fieldName, value := range existing.fieldValues {
+			fieldValues[fieldName] = value
+		}
+		for fieldName, value := range otherEntry.fieldValues {
+			if _, conflict := fieldValues[fieldName]; conflict && policy == KeepExisting {
+				continue
+			}
+			fieldValues[fieldName] = value
+		}
+		d.add(newEntry(existing.key, fieldValues))
+	}
+}
+
+// Diff compares the keys of the two databases. It returns the keys that are only contained in the new database
+// as added, and the keys that are only contained in the old database as removed, both in alphabetical order.
+func Diff(old, new *Database) (added, removed []string) {
+	oldKeys := old.keys()
+	newKeys := new.keys()
+
+	added = make([]string, 0)
+	removed = make([]string, 0)
+	i, j := 0, 0
+	for i < len(oldKeys) || j < len(newKeys) {
+		switch {
+		case j == len(newKeys) || (i < len(oldKeys) && oldKeys[i] < newKeys[j]):
+			removed = append(removed, oldKeys[i])
+			i++
+		case i == len(oldKeys) || newKeys[j] < oldKeys[i]:
+			added = append(added, newKeys[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return added, removed
+}
+
+// keys returns the keys of all entries in alphabetical order.
+func (d *Database) keys() []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	entries := d.entries()
+	result := make([]string, len(entries))
+	for i, entry := range entries {
+		result[i] = entry.key
+	}
+	return result
+}
+
+// Clone returns an independent copy of this database with the same field set, configuration, comments, and entries.
+// Modifications of the clone do not affect this database and vice versa.
+func (d *Database) Clone() *Database {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	result := NewDatabase(append(FieldSet{}, d.fieldSet...)...)
+	result.comments = append([]string(nil), d.comments...)
+	result.minQueryLength = d.minQueryLength
+	result.normalized = d.normalized
+	result.matchBaseCall = d.matchBaseCall
+	result.parallelism = d.parallelism
+	result.minOverlap = d.minOverlap
+	result.threshold = d.threshold
+	result.weightField = d.weightField
+	result.tieBreaker = d.tieBreaker
+	if d.cache != nil {
+		result.cache = newMatchCache(d.cache.size)
+	}
+
+	for _, entry := range d.entries() {
+		var fieldValues FieldValues
+		if entry.fieldValues != nil {
+			fieldValues = make(FieldValues, len(entry.fieldValues))
+			for fieldName, value := range entry.fieldValues {
+				fieldValues[fieldName] = value
+			}
+		}
+		result.add(newEntry(entry.key, fieldValues))
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package scp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabase_Merge(t *testing.T) {
+	tt := []struct {
+		desc     string
+		policy   MergePolicy
+		expected FieldValues
+	}{
+		{"keep existing", KeepExisting, FieldValues{FieldUserName: "Florian", "Sect": "B36", "Club": "DARC"}},
+		{"overwrite existing", OverwriteExisting, FieldValues{FieldUserName: "Flo", "Sect": "B36", "Club": "DARC"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			base := NewDatabase(FieldCall, FieldUserName, "Sect")
+			base.Add("DL3NEY", "DL3NEY", "Florian", "B36")
+			base.Add("DL1ABC", "DL1ABC", "Klaus", "B01")
+			supplement := NewDatabase(FieldCall, FieldUserName, "Club")
+			supplement.Add("DL3NEY", "DL3NEY", "Flo", "DARC")
+			supplement.Add("N1MM", "N1MM", "", "")
+
+			base.Merge(supplement, tc.policy)
+
+			assert.Equal(t, FieldSet{FieldCall, FieldUserName, "Sect", "Club"}, base.FieldSet())
+			assert.Equal(t, 3, base.Len())
+			fields, ok := base.Get("DL3NEY")
+			require.True(t, ok)
+			assert.Equal(t, tc.expected, fields)
+			fields, ok = base.Get("DL1ABC")
+			require.True(t, ok)
+			assert.Equal(t, FieldValues{FieldUserName: "Klaus", "Sect": "B01"}, fields)
+			assert.True(t, base.Contains("N1MM"))
+
+			fields, _ = supplement.Get("DL3NEY")
+			assert.Equal(t, FieldValues{FieldUserName: "Flo", "Club": "DARC"}, fields, "the other database is unchanged")
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := NewDatabase()
+	old.Add("DL1ABC")
+	old.Add("DL3NEY")
+	old.Add("N1MM")
+	new := NewDatabase()
+	new.Add("DL3NEY")
+	new.Add("K1ABC")
+	new.Add("ZZ9ZZ")
+
+	added, removed := Diff(old, new)
+	assert.Equal(t, []string{"K1ABC", "ZZ9ZZ"}, added)
+	assert.Equal(t, []string{"DL1ABC", "N1MM"}, removed)
+
+	added, removed = Diff(old, old)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+
+	added, removed = Diff(NewDatabase(), new)
+	assert.Equal(t, []string{"DL3NEY", "K1ABC", "ZZ9ZZ"}, added)
+	assert.Empty(t, removed)
+}
+
+func TestDatabase_Clone(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName)
+	database.Add("DL3NEY", "DL3NEY", "Florian")
+	database.Add("DL1ABC", "DL1ABC", "Klaus")
+	database.SetMinQueryLength(2)
+
+	clone := database.Clone()
+	assert.Equal(t, database.items, clone.items)
+	assert.Equal(t, database.FieldSet(), clone.FieldSet())
+	assert.Equal(t, 2, clone.MinQueryLength())
+
+	clone.Add("N1MM", "N1MM", "")
+	clone.Remove("DL1ABC")
+	clone.Merge(NewDatabase(FieldCall, "Sect"), KeepExisting)
+	clone.SetMinQueryLength(4)
+	clone.items['N']["DL3NEY"].fieldValues[FieldUserName] = "modified"
+
+	assert.Equal(t, 2, database.Len())
+	assert.True(t, database.Contains("DL1ABC"))
+	assert.False(t, database.Contains("N1MM"))
+	assert.Equal(t, FieldSet{FieldCall, FieldUserName}, database.FieldSet())
+	assert.Equal(t, 2, database.MinQueryLength())
+	fields, _ := database.Get("DL3NEY")
+	assert.Equal(t, "Florian", fields[FieldUserName])
+}
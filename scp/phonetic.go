@@ -0,0 +1,62 @@
+package scp
+
+import (
+	"sort"
+	"strings"
+)
+
+// phoneticClasses maps each letter to a representative of the letters that sound similar, based on the
+// groups of the Soundex algorithm. Other than Soundex, the vowels are not dropped but form their own class,
+// since their position is relevant in callsigns. Digits are kept as they are.
+var phoneticClasses = map[rune]rune{
+	'B': 'B', 'F': 'B', 'P': 'B', 'V': 'B',
+	'C': 'C', 'G': 'C', 'J': 'C', 'K': 'C', 'Q': 'C', 'S': 'C', 'X': 'C', 'Z': 'C',
+	'D': 'D', 'T': 'D',
+	'L': 'L',
+	'M': 'M', 'N': 'M',
+	'R': 'R',
+	'A': 'A', 'E': 'A', 'I': 'A', 'O': 'A', 'U': 'A', 'Y': 'A', 'H': 'A', 'W': 'A',
+}
+
+// PhoneticCode returns the phonetic code of the given key. Keys that sound similar have the same phonetic code,
+// e.g. "KN4" and "CN4".
+func PhoneticCode(key string) string {
+	var result strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		if class, ok := phoneticClasses[r]; ok {
+			result.WriteRune(class)
+		} else if r >= '0' && r <= '9' {
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
+
+// FindPhonetic returns all entries in database that sound similar to the given string, i.e. that have the same
+// phonetic code (see PhoneticCode). This catches errors that occur when callsigns are received by voice and are
+// not necessarily close in terms of the editing distance.
+func (d *Database) FindPhonetic(s string) ([]Match, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	source, ok := d.source(s)
+	if !ok || d.index == nil {
+		return nil, nil
+	}
+
+	options := d.searchOptions()
+	keys := d.index.phoneticKeys(d, PhoneticCode(source.key))
+	result := make([]Match, 0, len(keys))
+	for _, key := range keys {
+		entry, ok := d.lookup(key)
+		if !ok {
+			continue
+		}
+		dist, acc, assembly := editTo(source.key, entry.key)
+		result = append(result, options.newMatch(entry, dist, acc, assembly))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LessThan(result[j])
+	})
+	return result, nil
+}
@@ -0,0 +1,50 @@
+package scp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneticCode(t *testing.T) {
+	tt := []struct {
+		key      string
+		expected string
+	}{
+		{"KN4", "CM4"},
+		{"CN4", "CM4"},
+		{"w1aw", "A1AA"},
+		{"DL/W1AW", "DLA1AA"},
+		{"", ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.key, func(t *testing.T) {
+			assert.Equal(t, tc.expected, PhoneticCode(tc.key))
+		})
+	}
+}
+
+func TestDatabase_FindPhonetic(t *testing.T) {
+	database := NewDatabase()
+	database.Add("KN4ABC")
+	database.Add("DL3NEY")
+
+	matches, err := database.FindPhonetic("CN4ABC")
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "KN4ABC", matches[0].Key())
+
+	database.Add("CM4APC")
+	matches, err = database.FindPhonetic("CN4ABC")
+	require.NoError(t, err)
+	keys := make([]string, len(matches))
+	for i, match := range matches {
+		keys[i] = match.Key()
+	}
+	assert.Equal(t, []string{"KN4ABC", "CM4APC"}, keys)
+
+	matches, err = database.FindPhonetic("DL3NEX")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
@@ -9,41 +9,114 @@ is http://www.supercheckpartial.com/MASTER.SCP.
 1. The file is in plain text format (ASCII).
 2. Each line contains one callsign.
 3. Lines that begin with # are comments that can be ignored.
+
+# Concurrency
+
+A Database is safe to use concurrently from multiple goroutines without any additional synchronization.
+All methods that read the database share a read lock, so concurrent searches do not block each other.
+Only the methods that modify the database or its configuration take the exclusive write lock.
 */
 package scp
 
 import (
 	"bufio"
+	"compress/gzip"
+	"container/heap"
+	"context"
+	"fmt"
 	"io"
+	"math"
+	"math/bits"
+	"runtime"
 	"sort"
 	"strings"
 	"sync"
+	"unicode"
+	"unsafe"
+
+	"github.com/ftl/hamradio/callsign"
+	"github.com/ftl/hamradio/locator"
 )
 
 // DefaultURL is the original URL of the MASTER.SCP file: http://www.supercheckpartial.com/MASTER.SCP
 const DefaultURL = "http://www.supercheckpartial.com/MASTER.SCP"
 
 // DefaultLocalFilename is the default name for the file that is used to store the contents of MASTER.SCP locally in the user's home directory.
-const DefaultLocalFilename = ".config/hamradio/MASTER.SCP"
+const DefaultLocalFilename = ".config/" + configFilename
 
-// Database represents the SCP database.
+// configFilename is the name of the local MASTER.SCP file relative to the user's configuration directory.
+const configFilename = "hamradio/MASTER.SCP"
+
+// Database represents the SCP database. It is safe to use a Database concurrently from multiple goroutines.
 type Database struct {
-	fieldSet FieldSet
-	items    map[byte]entrySet
+	lock           sync.RWMutex
+	fieldSet       FieldSet
+	items          map[byte]entrySet
+	comments       []string
+	minQueryLength int
+	normalized     bool
+	matchBaseCall  bool
+	parallelism    int
+	minOverlap     int
+	threshold      float64
+	weightField    FieldName
+	tieBreaker     FieldName
+	index          *keyIndex
+	cache          *matchCache
+	workersLock    sync.Mutex
+	workers        *workerPool
 }
 
-var SCPFormat = EntryParserFunc(func(line string) (Entry, bool) {
-	if strings.HasPrefix(line, "#") {
+// DefaultMinQueryLength is the default minimum length of a string to search for in the database.
+const DefaultMinQueryLength = 3
+
+// SCPFormat parses the lines of a MASTER.SCP file. Lines that begin with # are comments.
+var SCPFormat = EntryParserFunc(NewSCPFormat().ParseEntry)
+
+// SCPParser parses the lines of a file in the SCP format with configurable comment prefixes (see NewSCPFormat).
+type SCPParser struct {
+	commentPrefixes []string
+}
+
+// NewSCPFormat returns an EntryParser for files in the SCP format that use the given prefixes for comment lines.
+// If no prefix is given, lines that begin with # are comments.
+func NewSCPFormat(commentPrefixes ...string) *SCPParser {
+	if len(commentPrefixes) == 0 {
+		commentPrefixes = []string{"#"}
+	}
+	return &SCPParser{
+		commentPrefixes: commentPrefixes,
+	}
+}
+
+// ParseEntry parses the given line and returns the corresponding entry.
+// If the line is a comment, this method returns false in the second return value.
+func (p *SCPParser) ParseEntry(line string) (Entry, bool) {
+	if _, ok := p.ParseComment(line); ok {
 		return Entry{}, false
 	}
 	return newEntry(line, nil), true
-})
+}
+
+// ParseComment returns the text of the given line without the comment prefix, if the line is a comment.
+// If the line begins with none of the comment prefixes of this parser, this method returns false in the second
+// return value.
+func (p *SCPParser) ParseComment(line string) (string, bool) {
+	for _, prefix := range p.commentPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(line[len(prefix):]), true
+		}
+	}
+	return "", false
+}
 
 type Match struct {
 	Entry
-	distance distance
-	accuracy accuracy
-	Assembly MatchingAssembly
+	distance   distance
+	accuracy   accuracy
+	Assembly   MatchingAssembly
+	weight     float64
+	tieBreaker string
 }
 
 // LessThan returns true if this match is less than the other based on the default ordering for matches (the better the lesser).
@@ -56,63 +129,581 @@ func (m Match) LessThan(o Match) bool {
 	if mLongestPart != oLongestPart {
 		return mLongestPart > oLongestPart
 	}
+	if m.weight != o.weight {
+		return m.weight > o.weight
+	}
+	if m.tieBreaker != o.tieBreaker {
+		return m.tieBreaker > o.tieBreaker
+	}
 	if len(m.key) != len(o.key) {
 		return len(m.key) < len(o.key)
 	}
 	return m.key < o.key
 }
 
+// Highlight returns the key of the matching entry with the parts that match the search input exactly in square brackets,
+// e.g. "[DL]2[ABC]" for a search for "DL1ABC".
+func (m Match) Highlight() string {
+	var result strings.Builder
+	for _, part := range m.Assembly {
+		switch part.OP {
+		case Delete:
+			continue
+		case NOP:
+			result.WriteString("[" + part.Value + "]")
+		default:
+			result.WriteString(part.Value)
+		}
+	}
+	return result.String()
+}
+
+// MatchedRanges returns the positions of the parts of the matching entry's key that match the search input exactly,
+// the same parts that Highlight puts in square brackets. Each range contains the start index and the end index
+// (exclusive) in bytes, e.g. [[0 2] [3 6]] for the key "DL2ABC" in a search for "DL1ABC", so that key[start:end]
+// is the matching part.
+func (m Match) MatchedRanges() [][2]int {
+	result := make([][2]int, 0, len(m.Assembly))
+	position := 0
+	for _, part := range m.Assembly {
+		switch part.OP {
+		case Delete:
+			continue
+		case NOP:
+			result = append(result, [2]int{position, position + len(part.Value)})
+		}
+		position += len(part.Value)
+	}
+	return result
+}
+
+// Weight returns the weight of the matching entry (see Database.SetWeightField).
+func (m Match) Weight() float64 {
+	return m.weight
+}
+
 func (m Match) Accuracy() float64 {
 	return float64(m.accuracy)
 }
 
+// Percent returns the accuracy of this match as percentage, rounded to the nearest integer between 0 and 100.
+func (m Match) Percent() int {
+	return int(math.Round(float64(m.accuracy) * 100))
+}
+
 // Read the database from a reader using the SCP format.
 func ReadSCP(r io.Reader) (*Database, error) {
 	return Read(r, SCPFormat)
 }
 
+// ReadGzip reads the database from a reader that provides gzip compressed data in the SCP format, e.g. MASTER.SCP.gz.
+func ReadGzip(r io.Reader) (*Database, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+	return ReadSCP(gzipReader)
+}
+
 // Read the database from a reader unsing the given entry parser.
+// Lines must not be longer than DefaultMaxLineLength, otherwise an error is returned.
 func Read(r io.Reader, parser EntryParser) (*Database, error) {
-	database := &Database{
-		items:    make(map[byte]entrySet),
-		fieldSet: FieldSet{},
+	return read(r, parser, DefaultMaxLineLength, nil, nil)
+}
+
+// DefaultMaxLineLength is the maximum length of a line in bytes that is accepted by Read and its variants: 1 MiB.
+const DefaultMaxLineLength = 1024 * 1024
+
+// ReadWithMaxLineLength reads the database from a reader using the given entry parser like Read, but accepts lines
+// with the given maximum length in bytes. If a line is longer, reading is aborted and an error is returned, e.g. for
+// a corrupted file without any line breaks. If the given length is zero or less, the DefaultMaxLineLength is used.
+func ReadWithMaxLineLength(r io.Reader, parser EntryParser, maxLineLength int) (*Database, error) {
+	if maxLineLength <= 0 {
+		maxLineLength = DefaultMaxLineLength
+	}
+	return read(r, parser, maxLineLength, nil, nil)
+}
+
+// ReadStats provides information about the content of a file that was read with ReadWithStats.
+type ReadStats struct {
+	// Entries is the number of lines that contain an entry, including the duplicates.
+	Entries int
+	// Duplicates contains the key of each entry that was already read from a previous line, once for each additional
+	// occurrence. The later entry replaces the previous one in the database.
+	Duplicates []string
+	// Skipped is the number of lines that were rejected by the parser, without empty lines, comments, and directives
+	// (see ReadWithErrorCallback). A high number indicates a broken file, e.g. with the wrong delimiter.
+	Skipped int
+}
+
+// ReadWithStats reads the database from a reader using the given entry parser like Read, and additionally
+// provides statistics about the content, e.g. the keys that occur more than once or the number of skipped lines.
+func ReadWithStats(r io.Reader, parser EntryParser) (*Database, ReadStats, error) {
+	var stats ReadStats
+	database, err := read(r, parser, DefaultMaxLineLength, &stats, nil)
+	return database, stats, err
+}
+
+// ReadWithErrorCallback reads the database from a reader using the given entry parser like Read, and calls onError
+// for each line that is rejected by the parser, with the line number (starting at 1) and the content of the line.
+// Empty lines, comments, and directives are not reported. If the parser implements CommentParser, it decides which
+// lines are comments, otherwise these are the lines that begin with #, ;, or !!.
+func ReadWithErrorCallback(r io.Reader, parser EntryParser, onError func(lineNo int, line string)) (*Database, error) {
+	return read(r, parser, DefaultMaxLineLength, nil, func(lineNo int, line string) error {
+		onError(lineNo, line)
+		return nil
+	})
+}
+
+// ReadStrict reads the database from a reader using the given entry parser like Read, but returns an error
+// with the line number for the first line that is rejected by the parser. Empty lines, comments, and directives
+// are allowed (see ReadWithErrorCallback).
+func ReadStrict(r io.Reader, parser EntryParser) (*Database, error) {
+	return read(r, parser, DefaultMaxLineLength, nil, func(lineNo int, line string) error {
+		return fmt.Errorf("invalid entry in line %d: %q", lineNo, line)
+	})
+}
+
+// read reads the database from a reader using the given entry parser. Lines that are longer than maxLineLength
+// abort reading with an error. If stats is not nil, it is updated with the content of each line. If onError is not nil,
+// it is called for each line that is rejected by the parser and is not a comment or directive
+// (see isCommentOrDirective). If onError returns an error, reading is aborted.
+func read(r io.Reader, parser EntryParser, maxLineLength int, stats *ReadStats, onError func(lineNo int, line string) error) (*Database, error) {
+	database := NewDatabase()
+	reader := bufio.NewReader(r)
+	skipByteOrderMark(reader)
+	lines := bufio.NewScanner(reader)
+	// the buffer grows up to the maximum length, plus the line break
+	initialSize := bufio.MaxScanTokenSize
+	if initialSize > maxLineLength+2 {
+		initialSize = maxLineLength + 2
 	}
-	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 0, initialSize), maxLineLength+2)
+	lineNo := 0
 	for lines.Scan() {
-		line := strings.TrimSpace(lines.Text())
-		if len(line) == 0 {
+		lineNo++
+		line := lines.Text()
+		if len(line) > maxLineLength {
+			return nil, fmt.Errorf("line %d is longer than %d bytes", lineNo, maxLineLength)
+		}
+		if database.readLine(line, parser, stats) {
 			continue
 		}
-		entry, ok := parser.ParseEntry(line)
-		if !ok {
+		if stats != nil {
+			stats.Skipped++
+		}
+		if onError == nil {
 			continue
 		}
-		database.add(entry)
+		if err := onError(lineNo, line); err != nil {
+			return nil, err
+		}
+	}
+	if err := lines.Err(); err == bufio.ErrTooLong {
+		return nil, fmt.Errorf("line %d is longer than %d bytes", lineNo+1, maxLineLength)
+	} else if err != nil {
+		return nil, err
 	}
 
 	return database, nil
 }
 
-func NewDatabase(fieldNames ...FieldName) *Database {
-	var fieldSet FieldSet
-	if len(fieldNames) > 0 {
-		fieldSet = FieldSet(fieldNames)
-	} else {
-		fieldSet = FieldSet{}
+// readLine adds the entry from the given line to the database, or keeps the line as comment.
+// If stats is not nil, it is updated with the content of the line.
+// readLine returns false if the line is rejected by the parser and is not empty, a comment, or a directive.
+func (d *Database) readLine(line string, parser EntryParser, stats *ReadStats) bool {
+	line = strings.TrimSpace(line)
+	if len(line) == 0 {
+		return true
+	}
+	entry, ok := parser.ParseEntry(line)
+	if !ok {
+		if comment, ok := parseComment(line, parser); ok {
+			d.comments = append(d.comments, comment)
+		}
+		return isCommentOrDirective(line, parser)
+	}
+	if stats != nil {
+		stats.Entries++
+		if _, exists := d.existing(entry.key); exists {
+			stats.Duplicates = append(stats.Duplicates, entry.key)
+		}
+	}
+	d.add(entry)
+	return true
+}
+
+// commentAndDirectivePrefixes are the prefixes of the lines that contain comments (# and ; in TRMASTER files)
+// or directives (!! in call history files). They are used if the parser does not implement CommentParser.
+var commentAndDirectivePrefixes = []string{"#", ";", "!!"}
+
+func isCommentOrDirective(line string, parser EntryParser) bool {
+	if commentParser, ok := parser.(CommentParser); ok {
+		_, isComment := commentParser.ParseComment(line)
+		return isComment
+	}
+	for _, prefix := range commentAndDirectivePrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
 	}
-	return &Database{
+	return false
+}
+
+// parseComment returns the text of the given line if it is a comment. If the parser implements CommentParser,
+// it decides which lines are comments, otherwise the lines that begin with # are comments.
+func parseComment(line string, parser EntryParser) (string, bool) {
+	if commentParser, ok := parser.(CommentParser); ok {
+		return commentParser.ParseComment(line)
+	}
+	if strings.HasPrefix(line, "#") {
+		return strings.TrimSpace(line[1:]), true
+	}
+	return "", false
+}
+
+// utf8ByteOrderMark is written by some editors at the beginning of a file.
+const utf8ByteOrderMark = "\xEF\xBB\xBF"
+
+func skipByteOrderMark(r *bufio.Reader) {
+	prefix, err := r.Peek(len(utf8ByteOrderMark))
+	if err == nil && string(prefix) == utf8ByteOrderMark {
+		r.Discard(len(prefix))
+	}
+}
+
+// WriteSCP writes the database to a writer using the SCP format. The given header lines are written as comments
+// at the beginning, e.g. WriteSCP(w, d, d.Comments()...) re-emits the comments that were read from the original file.
+// The entries are written in alphabetical order.
+func WriteSCP(w io.Writer, d *Database, header ...string) error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	out := bufio.NewWriter(w)
+	for _, line := range header {
+		_, err := fmt.Fprintf(out, "# %s\n", line)
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range d.entries() {
+		_, err := fmt.Fprintln(out, entry.key)
+		if err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
+
+// NewDatabase returns a new empty Database with the given field set.
+// The database searches with a pool of goroutines that stop when they are idle for a while. Call Close to stop them
+// immediately when the database is not needed anymore.
+func NewDatabase(fieldNames ...FieldName) *Database {
+	return NewDatabaseWith(WithFields(fieldNames...))
+}
+
+// Option configures a Database that is created with NewDatabaseWith.
+type Option func(*Database)
+
+// NewDatabaseWith returns a new empty Database that is configured with the given options.
+// Without any options, the database has an empty field set and the default configuration.
+func NewDatabaseWith(opts ...Option) *Database {
+	result := &Database{
 		items:    make(map[byte]entrySet),
-		fieldSet: fieldSet,
+		fieldSet: FieldSet{},
+		index:    new(keyIndex),
+	}
+	for _, opt := range opts {
+		opt(result)
+	}
+	return result
+}
+
+// WithFields sets the field set of the database.
+func WithFields(fieldNames ...FieldName) Option {
+	return func(d *Database) {
+		if len(fieldNames) > 0 {
+			d.fieldSet = FieldSet(fieldNames)
+		}
+	}
+}
+
+// WithThreshold sets the minimum accuracy of the matches returned by Find (see Database.SetThreshold).
+func WithThreshold(threshold float64) Option {
+	return func(d *Database) {
+		d.threshold = math.Min(threshold, 1)
+	}
+}
+
+// WithMinQueryLen sets the minimum length of a string to search for in the database (see Database.SetMinQueryLength).
+func WithMinQueryLen(length int) Option {
+	return func(d *Database) {
+		d.minQueryLength = length
+	}
+}
+
+// WithNormalization enables the normalization of keys (see Database.SetNormalization).
+func WithNormalization() Option {
+	return func(d *Database) {
+		d.normalized = true
+	}
+}
+
+// WithParallelism sets the number of goroutines that are used to search the database (see Database.SetParallelism).
+func WithParallelism(parallelism int) Option {
+	return func(d *Database) {
+		d.parallelism = parallelism
 	}
 }
 
 // FieldSet returns the set of additional data fields available per entry.
-func (d Database) FieldSet() FieldSet {
+func (d *Database) FieldSet() FieldSet {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
 	return d.fieldSet
 }
 
+// MinQueryLength returns the minimum length of a string to search for in the database. Shorter strings do not return any matches.
+func (d *Database) MinQueryLength() int {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.effectiveMinQueryLength()
+}
+
+func (d *Database) effectiveMinQueryLength() int {
+	if d.minQueryLength <= 0 {
+		return DefaultMinQueryLength
+	}
+	return d.minQueryLength
+}
+
+// SetMinQueryLength sets the minimum length of a string to search for in the database.
+// If the given length is zero or less, the DefaultMinQueryLength is used.
+func (d *Database) SetMinQueryLength(length int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.cache.invalidate()
+	d.minQueryLength = length
+}
+
+// MinFingerprintOverlap returns the minimum number of distinct characters that an entry must have in common with the
+// string to search for to be considered as match (see SetMinFingerprintOverlap).
+func (d *Database) MinFingerprintOverlap() int {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.effectiveMinOverlap()
+}
+
+func (d *Database) effectiveMinOverlap() int {
+	if d.minOverlap <= 0 {
+		return 1
+	}
+	return d.minOverlap
+}
+
+// SetMinFingerprintOverlap sets the minimum number of distinct characters that an entry must have in common with the
+// string to search for to be considered as match. Entries with less characters in common are skipped before their
+// similarity is computed, which speeds up the search for long strings that share single characters with many entries.
+// If the string to search for has less distinct characters, all of them are required. If the given number is one or
+// less, every entry that has at least one character in common with the string to search for is considered.
+func (d *Database) SetMinFingerprintOverlap(overlap int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.cache.invalidate()
+	d.minOverlap = overlap
+}
+
+// Threshold returns the minimum accuracy of the matches that are returned by Find and the other search functions
+// that do not take an explicit threshold.
+func (d *Database) Threshold() float64 {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.effectiveThreshold()
+}
+
+func (d *Database) effectiveThreshold() float64 {
+	if d.threshold <= 0 {
+		return DefaultAccuracyThreshold
+	}
+	return d.threshold
+}
+
+// SetThreshold sets the minimum accuracy of the matches that are returned by Find and the other search functions
+// that do not take an explicit threshold. A higher threshold returns less, but closer matches. If the given threshold
+// is zero or less, the DefaultAccuracyThreshold is used. A threshold greater than 1 is reduced to 1, i.e. only exact
+// matches are returned.
+func (d *Database) SetThreshold(threshold float64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.cache.invalidate()
+	d.threshold = math.Min(threshold, 1)
+}
+
+// Parallelism returns the number of goroutines that are used to search the database.
+func (d *Database) Parallelism() int {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.effectiveParallelism()
+}
+
+func (d *Database) effectiveParallelism() int {
+	if d.parallelism <= 0 {
+		return runtime.NumCPU()
+	}
+	return d.parallelism
+}
+
+// SetParallelism sets the number of goroutines that are used to search the database. The goroutines are started on
+// demand, are reused for all following searches and stop when they are idle for a while or when Close is called.
+// If the given number is zero or less, the number of CPUs is used.
+func (d *Database) SetParallelism(parallelism int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.parallelism = parallelism
+	d.stopWorkers()
+}
+
+// SetWeightField sets the field that contains the weight of each entry. The weight is a number, the higher the weight,
+// the more relevant is the entry. If two matches are equally close to the search input, the match with the higher
+// weight comes first. Entries without a valid number in the weight field have the weight 0.
+// Use FieldIgnore to disable the weighting.
+func (d *Database) SetWeightField(fieldName FieldName) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.cache.invalidate()
+	d.weightField = fieldName
+}
+
+// SetTieBreaker sets the field that is used to order matches that are equally close to the search input and have the
+// same weight. The match with the greater value in this field comes first, e.g. the most recent date in the format
+// YYYY-MM-DD. Without a tie breaker (or with FieldIgnore), those matches are ordered by the length of their key and
+// then alphabetically.
+func (d *Database) SetTieBreaker(fieldName FieldName) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.cache.invalidate()
+	d.tieBreaker = fieldName
+}
+
+// SetNormalization enables or disables the normalization of keys. If enabled, the keys are normalized (see Normalize),
+// both for the entries of the database and for the strings to search for. Keys are always trimmed and converted to
+// upper case, independent from this setting.
+//
+// Enabling the normalization changes the keys of the existing entries and hence the keys of the matches.
+// Entries that have the same normalized key are merged. Disabling the normalization does not restore the original keys.
+func (d *Database) SetNormalization(enabled bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.normalized == enabled {
+		return
+	}
+	d.normalized = enabled
+	d.cache.invalidate()
+	if !enabled {
+		return
+	}
+
+	entries := d.entries()
+	d.items = make(map[byte]entrySet)
+	for _, entry := range entries {
+		d.add(entry)
+	}
+}
+
+// StripPunctuation removes all characters that are neither letters nor digits from the beginning and the end
+// of the given key, e.g. "<W1AW>," becomes "W1AW".
+func StripPunctuation(key string) string {
+	return strings.TrimFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SetMatchBaseCall enables or disables matching against the base call. If enabled, the search functions reduce the
+// string to search for to its base call (see StripPortable), e.g. a search for "DL/W1AW/P" finds "W1AW".
+func (d *Database) SetMatchBaseCall(enabled bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.cache.invalidate()
+	d.matchBaseCall = enabled
+}
+
+// StripPortable removes the prefix, the suffix, and the working condition from the given callsign and returns
+// only the base call, e.g. "DL/W1AW/P" becomes "W1AW", and "W1AW/7" becomes "W1AW" (see BaseCall).
+func StripPortable(call string) string {
+	return BaseCall(call)
+}
+
+// BaseCall returns the home callsign of the given callsign in upper case, without the prefix, the suffix, and the
+// working condition, e.g. "DL/W1AW/P" becomes "W1AW". If the given string is not a valid callsign (see IsValidCallsign),
+// the longest part between the slashes that contains a digit is returned, e.g. "VP2E/W1AW/QRPP" becomes "W1AW".
+// If no part contains a digit, the longest part is returned.
+func BaseCall(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	parsed, err := callsign.Parse(s)
+	if err == nil {
+		return parsed.BaseCall
+	}
+
+	var result, longest string
+	for _, part := range strings.Split(s, "/") {
+		if len(part) > len(result) && strings.ContainsAny(part, "0123456789") {
+			result = part
+		}
+		if len(part) > len(longest) {
+			longest = part
+		}
+	}
+	if result == "" {
+		return longest
+	}
+	return result
+}
+
+// IsValidCallsign returns true if the given string has the structure of a callsign: a prefix of letters and digits,
+// followed by a digit and a suffix that ends with a letter, optionally with a portable prefix or suffix and a working
+// condition, e.g. "DL1ABC", "3DA0RU", or "K/DL1ABC/P". It does not check if the prefix is actually assigned.
+func IsValidCallsign(s string) bool {
+	_, err := callsign.Parse(s)
+	return err == nil
+}
+
+// Normalize returns the given string as consistent key, independent from how the callsign was typed:
+//   - the whitespace at the beginning and the end is removed,
+//   - all other characters that are neither letters nor digits at the beginning and the end are removed
+//     (see StripPunctuation), e.g. quotes, brackets, or a trailing slash,
+//   - all letters are converted to upper case.
+//
+// The characters within the key are kept, e.g. " <dl/w1aw/p>, " becomes "DL/W1AW/P".
+func Normalize(s string) string {
+	return strings.ToUpper(StripPunctuation(strings.TrimSpace(s)))
+}
+
+// ValidGrid returns true if the given string is a valid maidenhead locator with one to four pairs of characters,
+// e.g. "JO", "JO62", or "JO62qm". The case of the letters does not matter.
+func ValidGrid(s string) bool {
+	_, err := locator.Parse(s)
+	return err == nil
+}
+
+// normalize returns the given key in the form that is used in the database. If the normalization is disabled,
+// the key is only trimmed and converted to upper case.
+func (d *Database) normalize(key string) string {
+	if !d.normalized {
+		return strings.ToUpper(strings.TrimSpace(key))
+	}
+	return Normalize(key)
+}
+
+// Comments returns the comment lines that were read from the original file, without the comment prefix.
+func (d *Database) Comments() []string {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.comments
+}
+
 // FindStrings returns all strings in database that partially match the given string
-func (d Database) FindStrings(s string) ([]string, error) {
+func (d *Database) FindStrings(s string) ([]string, error) {
 	allMatches, err := d.Find(s)
 	if err != nil {
 		return nil, err
@@ -126,17 +717,250 @@ func (d Database) FindStrings(s string) ([]string, error) {
 	return result, nil
 }
 
+// Contains returns true if the database contains an entry with the given key.
+func (d *Database) Contains(key string) bool {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	_, ok := d.lookup(key)
+	return ok
+}
+
+// Exact returns the entry with the given key as exact match, without fuzzy matching.
+func (d *Database) Exact(key string) (Match, bool) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	entry, ok := d.lookup(key)
+	if !ok {
+		return Match{}, false
+	}
+	distance, accuracy, assembly := entry.EditTo(entry)
+	return d.searchOptions().newMatch(entry, distance, accuracy, assembly), true
+}
+
+func (d *Database) lookup(key string) (Entry, bool) {
+	return d.existing(d.normalize(key))
+}
+
+// existing returns the entry with the given normalized key. Since the entry is contained in the bucket of the first byte
+// of its fingerprint, the fingerprint of the key does not need to be computed completely.
+func (d *Database) existing(key string) (Entry, bool) {
+	b, ok := firstFingerprintByte(key)
+	if !ok {
+		return Entry{}, false
+	}
+	entry, ok := d.items[b][key]
+	return entry, ok
+}
+
+// Get returns a copy of the field values of the entry with the given key, or false if there is no such entry.
+func (d *Database) Get(key string) (FieldValues, bool) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	entry, ok := d.lookup(key)
+	if !ok {
+		return nil, false
+	}
+	result := make(FieldValues, len(entry.fieldValues))
+	for fieldName, value := range entry.fieldValues {
+		result[fieldName] = value
+	}
+	return result, true
+}
+
+// DefaultAccuracyThreshold is the minimum accuracy of the matches returned by Find, unless the database has a different
+// threshold (see Database.SetThreshold).
+const DefaultAccuracyThreshold = 0.65
+
 // Find returns all entries in database that are similar to the given string.
-func (d Database) Find(s string) ([]Match, error) {
-	if len(s) < 3 {
+func (d *Database) Find(s string) ([]Match, error) {
+	return d.find(context.Background(), s, nil)
+}
+
+// FindWithThreshold returns all entries in database that are similar to the given string with at least the given
+// accuracy. The threshold must be between 0 and 1, a higher threshold returns less, but closer matches.
+func (d *Database) FindWithThreshold(s string, threshold float64) ([]Match, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("invalid accuracy threshold %v, must be between 0 and 1", threshold)
+	}
+	return d.find(context.Background(), s, func(options *searchOptions) {
+		options.threshold = accuracy(threshold)
+	})
+}
+
+// FindContext returns all entries in database that are similar to the given string. The search is stopped when
+// the given context is cancelled, in this case the context's error is returned.
+func (d *Database) FindContext(ctx context.Context, s string) ([]Match, error) {
+	return d.find(ctx, s, nil)
+}
+
+// FindStream sends all entries in database that are similar to the given string to the returned channel as soon
+// as they are found. The matches are not sorted. The channel is closed when the search is complete or the given
+// context is cancelled. The caller must either read the channel until it is closed or cancel the context, otherwise
+// the goroutines of the search never stop. The search works on a snapshot of the database, the database can be
+// modified while the matches are read. Each stream is searched with its own goroutines, so a stream that is read
+// slowly does not block other searches.
+func (d *Database) FindStream(ctx context.Context, s string) (<-chan Match, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	d.lock.RLock()
+	result := make(chan Match)
+	source, ok := d.source(s)
+	if !ok {
+		d.lock.RUnlock()
+		close(result)
+		return result, nil
+	}
+	buckets := d.snapshotBuckets(source)
+	options := d.searchOptions()
+	workers := newWorkerPool(d.effectiveParallelism())
+	d.lock.RUnlock()
+
+	matches := make(chan Match, 100)
+	go func() {
+		defer workers.close()
+		searchBuckets(ctx, workers, matches, source, buckets, options)
+		close(matches)
+	}()
+	go forwardMatches(ctx, result, matches)
+
+	return result, nil
+}
+
+// FindWithScorer returns all entries in database that are similar to the given string, using the given Scorer to
+// compute the similarity.
+func (d *Database) FindWithScorer(s string, scorer Scorer) ([]Match, error) {
+	return d.find(context.Background(), s, func(options *searchOptions) {
+		options.scorer = scorer
+	})
+}
+
+// FindFiltered returns all entries in database that are similar to the given string and that have the given value
+// in the given field. Entries that do not have the field set are not returned.
+func (d *Database) FindFiltered(s string, field FieldName, value string) ([]Match, error) {
+	return d.find(context.Background(), s, func(options *searchOptions) {
+		options.filter = func(e Entry) bool {
+			actual, ok := e.fieldValues[field]
+			return ok && actual == value
+		}
+	})
+}
+
+// FindN returns at most the n best entries in database that are similar to the given string.
+func (d *Database) FindN(s string, n int) ([]Match, error) {
+	if n <= 0 {
 		return nil, nil
 	}
-	source := newEntry(s, nil)
+	return d.find(context.Background(), s, func(options *searchOptions) {
+		options.limit = n
+	})
+}
+
+// FindBest returns the entry in database that is most similar to the given string, or false if no entry is similar
+// enough to the given string. Only the best match is kept during the search, the other matches are neither collected
+// nor sorted.
+func (d *Database) FindBest(s string) (Match, bool) {
+	matches, err := d.FindN(s, 1)
+	if err != nil || len(matches) == 0 {
+		return Match{}, false
+	}
+	return matches[0], true
+}
+
+// find returns the entries that are similar to the given string with at least the given accuracy.
+// If limit is greater than zero, only the best limit matches are returned.
+// searchOptions control how the database is searched.
+type searchOptions struct {
+	threshold   accuracy
+	scorer      Scorer
+	limit       int
+	minOverlap  int
+	weightField FieldName
+	tieBreaker  FieldName
+	filter      func(Entry) bool
+}
+
+func (o searchOptions) newMatch(e Entry, dist distance, acc accuracy, assembly MatchingAssembly) Match {
+	return Match{
+		Entry:      e,
+		distance:   dist,
+		accuracy:   acc,
+		Assembly:   assembly,
+		weight:     e.weight(o.weightField),
+		tieBreaker: e.Get(o.tieBreaker),
+	}
+}
+
+func (d *Database) searchOptions() searchOptions {
+	return searchOptions{
+		threshold:   accuracy(d.effectiveThreshold()),
+		scorer:      EditDistanceScorer,
+		minOverlap:  d.effectiveMinOverlap(),
+		weightField: d.weightField,
+		tieBreaker:  d.tieBreaker,
+	}
+}
+
+func (d *Database) find(ctx context.Context, s string, configure func(*searchOptions)) ([]Match, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	options := d.searchOptions()
+	if configure != nil {
+		configure(&options)
+	}
+	source, ok := d.source(s)
+	if !ok {
+		return nil, nil
+	}
+	cacheable := configure == nil
+	if cacheable {
+		if result, ok := d.cache.get(source.key); ok {
+			return result, nil
+		}
+	}
 
 	matches := make(chan Match, 100)
 	merged := make(chan []Match)
-	waiter := &sync.WaitGroup{}
+	// start collecting before searching, otherwise the search blocks as soon as the matches channel is full
+	go collectMatches(merged, matches, options.limit)
 
+	d.search(ctx, matches, source, options)
+	close(matches)
+	result := <-merged
+	close(merged)
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if cacheable {
+		d.cache.put(source.key, result)
+	}
+	return result, nil
+}
+
+// source returns the Entry to search for in the database, or false if the given string is too short.
+func (d *Database) source(s string) (Entry, bool) {
+	s = d.normalize(s)
+	if d.matchBaseCall {
+		s = StripPortable(s)
+	}
+	if len(s) < d.effectiveMinQueryLength() {
+		return Entry{}, false
+	}
+	return newEntry(s, nil), true
+}
+
+// search sends all entries that are similar to the source with at least the given accuracy to the matches channel.
+// An entry may be sent more than once. search returns when all entries are processed or the context is cancelled.
+func (d *Database) search(ctx context.Context, matches chan<- Match, source Entry, options searchOptions) {
+	searchBuckets(ctx, d.workerPool(), matches, source, d.buckets(source), options)
+}
+
+// buckets returns the entry sets that share at least one byte of the fingerprint with the given source.
+func (d *Database) buckets(source Entry) []entrySet {
+	result := make([]entrySet, 0, len(source.fingerprint))
 	byteMap := make(map[byte]bool)
 	for _, b := range source.fingerprint {
 		if byteMap[b] {
@@ -147,38 +971,105 @@ func (d Database) Find(s string) ([]Match, error) {
 		if !ok {
 			continue
 		}
+		result = append(result, entrySet)
+	}
+	return result
+}
 
-		waiter.Add(1)
-		go findMatches(matches, source, entrySet, waiter)
+// snapshotBuckets returns copies of the entry sets that share at least one byte of the fingerprint with the given
+// source. The copies can be searched without holding the lock of the database.
+func (d *Database) snapshotBuckets(source Entry) []entrySet {
+	buckets := d.buckets(source)
+	for i, bucket := range buckets {
+		snapshot := make(entrySet, len(bucket))
+		for key, entry := range bucket {
+			snapshot[key] = entry
+		}
+		buckets[i] = snapshot
 	}
-	go collectMatches(merged, matches)
+	return buckets
+}
 
-	waiter.Wait()
-	close(matches)
-	result := <-merged
-	close(merged)
-	return result, nil
+// searchBuckets searches the given buckets with the given worker pool (see search).
+func searchBuckets(ctx context.Context, workers *workerPool, matches chan<- Match, source Entry, buckets []entrySet, options searchOptions) {
+	jobs := make([]func(), len(buckets))
+	for i, bucket := range buckets {
+		bucket := bucket
+		jobs[i] = func() {
+			if ctx.Err() != nil {
+				return
+			}
+			findMatches(ctx, matches, source, bucket, options)
+		}
+	}
+	workers.run(jobs)
 }
 
-func findMatches(matches chan<- Match, input Entry, entries entrySet, waiter *sync.WaitGroup) {
-	defer waiter.Done()
-	const accuracyThreshold = 0.65
+func findMatches(ctx context.Context, matches chan<- Match, input Entry, entries entrySet, options searchOptions) {
+	inputMask := input.fingerprint.mask()
+	minOverlap := options.minOverlap
+	if distinct := bits.OnesCount64(inputMask); minOverlap > distinct {
+		minOverlap = distinct
+	}
+	for _, e := range entries {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
 
-	entries.Do(func(e Entry) {
-		distance, accuracy, assembly := input.EditTo(e)
-		if accuracy >= accuracyThreshold {
-			matches <- Match{e, distance, accuracy, assembly}
+		if minOverlap > 1 && bits.OnesCount64(inputMask&e.fingerprint.mask()) < minOverlap {
+			continue
 		}
-	})
+		if options.filter != nil && !options.filter(e) {
+			continue
+		}
+		dist, acc, assembly := options.scorer.Score(input.key, e.key)
+		if accuracy(acc) < options.threshold {
+			continue
+		}
+		select {
+		case matches <- options.newMatch(e, distance(dist), accuracy(acc), assembly):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// forwardMatches sends each distinct match to the result channel until the matches channel is closed.
+func forwardMatches(ctx context.Context, result chan<- Match, matches <-chan Match) {
+	defer close(result)
+	matchSet := make(map[string]bool)
+	for match := range matches {
+		if matchSet[match.key] {
+			continue
+		}
+		matchSet[match.key] = true
+
+		select {
+		case result <- match:
+		case <-ctx.Done():
+		}
+	}
 }
 
-func collectMatches(result chan<- []Match, matches <-chan Match) {
-	allMatches := make([]Match, 0)
-	matchSet := make(map[string]Match)
+func collectMatches(result chan<- []Match, matches <-chan Match, limit int) {
+	allMatches := make(worstMatchFirst, 0)
+	matchSet := make(map[string]bool)
 	for match := range matches {
-		if _, ok := matchSet[match.key]; !ok {
-			matchSet[match.key] = match
+		if matchSet[match.key] {
+			continue
+		}
+		matchSet[match.key] = true
+
+		switch {
+		case limit <= 0:
 			allMatches = append(allMatches, match)
+		case len(allMatches) < limit:
+			heap.Push(&allMatches, match)
+		case match.LessThan(allMatches[0]):
+			allMatches[0] = match
+			heap.Fix(&allMatches, 0)
 		}
 	}
 	sort.Slice(allMatches, func(i, j int) bool {
@@ -187,26 +1078,256 @@ func collectMatches(result chan<- []Match, matches <-chan Match) {
 	result <- allMatches
 }
 
-func (d Database) Add(key string, values ...string) {
-	var fieldValues FieldValues
-	if len(values) > 0 && len(values) == len(d.fieldSet) {
-		fieldValues = make(FieldValues, len(d.fieldSet))
-		for i, value := range values {
-			fieldName := d.fieldSet.Get(i)
-			// Skip the callsign and ignore fields because they are not stored in the database.
-			// The callsign is computed from the key, and the ignore field is not stored.
-			if fieldName == FieldCall || fieldName == FieldIgnore {
+// worstMatchFirst is a heap of matches that keeps the worst match at the top, so it can be replaced easily by a better one.
+type worstMatchFirst []Match
+
+func (h worstMatchFirst) Len() int           { return len(h) }
+func (h worstMatchFirst) Less(i, j int) bool { return h[j].LessThan(h[i]) }
+func (h worstMatchFirst) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *worstMatchFirst) Push(x interface{}) {
+	*h = append(*h, x.(Match))
+}
+
+func (h *worstMatchFirst) Pop() interface{} {
+	old := *h
+	n := len(old)
+	result := old[n-1]
+	*h = old[:n-1]
+	return result
+}
+
+func (d *Database) Add(key string, values ...string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	fieldValues, _ := d.fieldValues(values)
+	d.add(d.newEntry(key, fieldValues))
+}
+
+// AddChecked adds an entry with the given key and field values like Add, but returns an error if the number of values
+// does not match the field set of the database. In this case, the entry is not added. An entry without any values
+// is always added.
+func (d *Database) AddChecked(key string, values ...string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	fieldValues, err := d.fieldValues(values)
+	if err != nil {
+		return fmt.Errorf("cannot add %s: %w", key, err)
+	}
+	d.add(d.newEntry(key, fieldValues))
+	return nil
+}
+
+// fieldValues maps the given values to the fields of the database's field set.
+func (d *Database) fieldValues(values []string) (FieldValues, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if len(values) != len(d.fieldSet) {
+		return nil, fmt.Errorf("got %d values for %d fields", len(values), len(d.fieldSet))
+	}
+
+	result := make(FieldValues, len(d.fieldSet))
+	for i, value := range values {
+		fieldName := d.fieldSet.Get(i)
+		// Skip the callsign and ignore fields because they are not stored in the database.
+		// The callsign is computed from the key, and the ignore field is not stored.
+		if fieldName == FieldCall || fieldName == FieldIgnore {
+			continue
+		}
+		result[fieldName] = strings.TrimSpace(value)
+	}
+	return result, nil
+}
+
+// newEntry creates a new Entry with the given key and field values. If the database already contains an entry with the
+// same key, its fingerprint is reused instead of being computed again.
+func (d *Database) newEntry(key string, fieldValues FieldValues) Entry {
+	key = d.normalize(key)
+	if existing, ok := d.existing(key); ok {
+		return Entry{key: key, fingerprint: existing.fingerprint, fieldValues: fieldValues}
+	}
+	return newEntry(key, fieldValues)
+}
+
+// Remove removes the entry with the given key from the database. It returns true if the entry was found and removed.
+func (d *Database) Remove(key string) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	entry, ok := d.lookup(key)
+	if !ok {
+		return false
+	}
+
+	d.invalidate()
+	for _, b := range entry.fingerprint {
+		es := d.items[b]
+		delete(es, entry.key)
+		if len(es) == 0 {
+			delete(d.items, b)
+		}
+	}
+	return true
+}
+
+// SetFields updates the field values of the entry with the given key. The given values replace the existing values
+// of the same fields, all other fields of the entry are kept. It returns false if there is no entry with the given key.
+func (d *Database) SetFields(key string, fields FieldValues) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	entry, ok := d.lookup(key)
+	if !ok {
+		return false
+	}
+
+	fieldValues := make(FieldValues, len(entry.fieldValues)+len(fields))
+	for fieldName, value := range entry.fieldValues {
+		fieldValues[fieldName] = value
+	}
+	for fieldName, value := range fields {
+		fieldValues[fieldName] = value
+	}
+	entry.fieldValues = fieldValues
+	d.add(entry)
+	return true
+}
+
+// Clear removes all entries and comments from the database. The field set and the configuration of the database
+// are kept.
+func (d *Database) Clear() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.invalidate()
+	for b := range d.items {
+		delete(d.items, b)
+	}
+	d.comments = nil
+}
+
+// Len returns the number of distinct entries in the database.
+func (d *Database) Len() int {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	result := 0
+	for b, es := range d.items {
+		for _, e := range es {
+			// every entry is contained in the bucket of each byte of its fingerprint, count only the first
+			if e.fingerprint[0] == b {
+				result++
+			}
+		}
+	}
+	return result
+}
+
+// DatabaseStats provides information about the structure of a Database.
+type DatabaseStats struct {
+	// Entries is the number of distinct entries in the database.
+	Entries int
+	// Buckets is the number of fingerprint buckets. The entries are distributed over the buckets by the characters
+	// of their keys, there is one bucket for each letter or digit that occurs in any key.
+	Buckets int
+	// BucketSizes contains the number of entries in each bucket, by the character of the bucket. An entry is contained
+	// in the bucket of each character of its key. A search visits the buckets of all characters of the search input,
+	// hence large buckets of common characters make the search slower.
+	BucketSizes map[byte]int
+}
+
+// Stats returns information about the structure of the database.
+func (d *Database) Stats() DatabaseStats {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	result := DatabaseStats{
+		Buckets:     len(d.items),
+		BucketSizes: make(map[byte]int, len(d.items)),
+	}
+	for b, es := range d.items {
+		result.BucketSizes[b] = len(es)
+		for _, e := range es {
+			if e.fingerprint[0] == b {
+				result.Entries++
+			}
+		}
+	}
+	return result
+}
+
+// mapEntryOverhead is the approximate additional memory per map entry, for the buckets and the hashing of the map.
+const mapEntryOverhead = 16
+
+// ApproxSizeBytes returns an estimation of the memory in bytes that is used by the entries of the database.
+// The estimation considers the number of entries, the length of the keys and field values, and that each entry is
+// contained in the bucket of each character of its key. It is meant as an order of magnitude for capacity planning,
+// the actual memory usage depends on the Go runtime.
+func (d *Database) ApproxSizeBytes() int64 {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	bucketEntrySize := int64(unsafe.Sizeof("") + unsafe.Sizeof(Entry{}) + mapEntryOverhead)
+	fieldValueSize := int64(unsafe.Sizeof(FieldName("")) + unsafe.Sizeof("") + mapEntryOverhead)
+
+	var result int64
+	for b, es := range d.items {
+		result += int64(len(es)) * bucketEntrySize
+		for _, e := range es {
+			if e.fingerprint[0] != b {
 				continue
 			}
-			fieldValues[fieldName] = strings.TrimSpace(value)
+			result += int64(len(e.key) + cap(e.fingerprint))
+			for _, value := range e.fieldValues {
+				result += fieldValueSize + int64(len(value))
+			}
+		}
+	}
+	for _, comment := range d.comments {
+		result += int64(unsafe.Sizeof(comment) + uintptr(len(comment)))
+	}
+	return result
+}
+
+// Each calls the given function for each distinct entry in the database, in alphabetical order of the keys.
+// The iteration stops when the function returns false. The field values must not be modified.
+// The function is called with a snapshot of the entries, it may modify the database.
+func (d *Database) Each(f func(key string, fields FieldValues) bool) {
+	d.lock.RLock()
+	entries := d.entries()
+	d.lock.RUnlock()
+
+	for _, e := range entries {
+		if !f(e.key, e.fieldValues) {
+			return
 		}
 	}
+}
 
-	entry := newEntry(key, fieldValues)
-	d.add(entry)
+// entries returns all distinct entries of the database, sorted by their key.
+func (d *Database) entries() []Entry {
+	result := make([]Entry, 0)
+	for b, es := range d.items {
+		for _, e := range es {
+			// every entry is contained in the bucket of each byte of its fingerprint, use only the first to visit it once
+			if e.fingerprint[0] == b {
+				result = append(result, e)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].key < result[j].key
+	})
+	return result
 }
 
-func (d Database) add(entry Entry) {
+func (d *Database) add(entry Entry) {
+	d.invalidate()
+	if normalizedKey := d.normalize(entry.key); normalizedKey != entry.key {
+		entry = newEntry(normalizedKey, entry.fieldValues)
+	}
 	for _, b := range entry.fingerprint {
 		es, ok := d.items[b]
 		if !ok {
@@ -1,9 +1,15 @@
 package scp
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
 	"os"
+	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -81,6 +87,79 @@ func TestDatabase_Find(t *testing.T) {
 	}
 }
 
+func TestDatabase_FindWithThreshold(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := Read(file, SCPFormat)
+	require.NoError(t, err)
+
+	tt := []struct {
+		input     string
+		threshold float64
+		expected  []string
+	}{
+		{"DL1AB", 0.5, []string{"DL1ABC", "DK1AB", "DL2ABC"}},
+		{"DL1AB", 0.81, []string{"DL1ABC"}},
+		{"DK1AB", 0.55, []string{"DK1AB", "DL1ABC", "DK9BB"}},
+		{"DK1AB", 1, []string{"DK1AB"}},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%s %v", tc.input, tc.threshold), func(t *testing.T) {
+			matches, err := database.FindWithThreshold(tc.input, tc.threshold)
+			require.NoError(t, err)
+			actual := make([]string, len(matches))
+			for i, match := range matches {
+				actual[i] = match.Key()
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	_, err = database.FindWithThreshold("DL1AB", 1.1)
+	assert.Error(t, err)
+}
+
+func TestDatabase_SetThreshold(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := Read(file, SCPFormat)
+	require.NoError(t, err)
+	database.SetCacheSize(10)
+	assert.Equal(t, DefaultAccuracyThreshold, database.Threshold())
+	defaultMatches := mustFind(t, database, "DL1AB")
+
+	tt := []struct {
+		threshold float64
+		expected  float64
+	}{
+		{0.5, 0.5},
+		{0.81, 0.81},
+		{1, 1},
+		{1.1, 1},
+		{0, DefaultAccuracyThreshold},
+		{-1, DefaultAccuracyThreshold},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%v", tc.threshold), func(t *testing.T) {
+			database.SetThreshold(tc.threshold)
+			assert.Equal(t, tc.expected, database.Threshold())
+
+			expected, err := database.FindWithThreshold("DL1AB", tc.expected)
+			require.NoError(t, err)
+			assert.Equal(t, expected, mustFind(t, database, "DL1AB"))
+			actual, err := database.FindStrings("DL1AB")
+			require.NoError(t, err)
+			assert.Equal(t, matchKeys(expected), actual)
+		})
+	}
+	assert.Equal(t, defaultMatches, mustFind(t, database, "DL1AB"))
+
+	database.SetThreshold(0.81)
+	assert.Equal(t, 0.81, database.Clone().Threshold())
+}
+
 func TestDatabase_Add(t *testing.T) {
 	database := NewDatabase()
 	assert.Equal(t, 0, len(database.items))
@@ -170,3 +249,1247 @@ func TestDatabase_Add_HandleDuplicate(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteSCP(t *testing.T) {
+	database := NewDatabase()
+	database.Add("N1MM")
+	database.Add("2E0BNI")
+	database.Add("2E0AOZ")
+	database.Add("2E0AOZ")
+
+	buffer := new(bytes.Buffer)
+	err := WriteSCP(buffer, database, "generated for testing")
+	require.NoError(t, err)
+	assert.Equal(t, "# generated for testing\n2E0AOZ\n2E0BNI\nN1MM\n", buffer.String())
+
+	readDatabase, err := ReadSCP(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, database.items, readDatabase.items)
+}
+
+func TestDatabase_Comments(t *testing.T) {
+	const testSCP = `# Version 2023-03-01
+#generated by hand
+N1MM
+# trailing comment`
+
+	database, err := ReadSCP(strings.NewReader(testSCP))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Version 2023-03-01", "generated by hand", "trailing comment"}, database.Comments())
+
+	buffer := new(bytes.Buffer)
+	err = WriteSCP(buffer, database, database.Comments()...)
+	require.NoError(t, err)
+	assert.Equal(t, "# Version 2023-03-01\n# generated by hand\n# trailing comment\nN1MM\n", buffer.String())
+}
+
+func TestNewSCPFormat(t *testing.T) {
+	tt := []struct {
+		desc     string
+		prefixes []string
+		line     string
+		valid    bool
+	}{
+		{"default comment", nil, "# comment", false},
+		{"default entry", nil, "DL1ABC", true},
+		{"semicolon comment", []string{";", "//"}, "; comment", false},
+		{"slashes comment", []string{";", "//"}, "// comment", false},
+		{"hash is no comment", []string{";", "//"}, "#DL1ABC", true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			_, valid := NewSCPFormat(tc.prefixes...).ParseEntry(tc.line)
+			assert.Equal(t, tc.valid, valid)
+		})
+	}
+}
+
+func TestNewSCPFormat_Comments(t *testing.T) {
+	const testSCP = `// Version 2023-03-01
+; generated by hand
+N1MM
+#DL1ABC`
+
+	database, err := Read(strings.NewReader(testSCP), NewSCPFormat("//", ";"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Version 2023-03-01", "generated by hand"}, database.Comments())
+	assert.Equal(t, []string{"N1MM"}, mustFindStrings(t, database, "N1MM"))
+}
+
+func TestDatabase_Read_ByteOrderMark(t *testing.T) {
+	file, err := os.Open("testdata/BOM.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+
+	database, err := ReadSCP(file)
+	require.NoError(t, err)
+
+	matches, err := database.FindStrings("DJ8BB")
+	require.NoError(t, err)
+	require.NotEmpty(t, matches)
+	assert.Equal(t, "DJ8BB", matches[0])
+	assert.Equal(t, 1.0, mustFind(t, database, "DJ8BB")[0].Accuracy())
+}
+
+func mustFind(t *testing.T, database *Database, s string) []Match {
+	t.Helper()
+	matches, err := database.Find(s)
+	require.NoError(t, err)
+	return matches
+}
+
+func TestReadGzip(t *testing.T) {
+	buffer := new(bytes.Buffer)
+	compressor := gzip.NewWriter(buffer)
+	_, err := compressor.Write([]byte("# compressed\nDL3NEY\nN1MM\n"))
+	require.NoError(t, err)
+	require.NoError(t, compressor.Close())
+
+	database, err := ReadGzip(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL3NEY", "N1MM"}, []string{database.entries()[0].Key(), database.entries()[1].Key()})
+
+	_, err = ReadGzip(strings.NewReader("DL3NEY\n"))
+	assert.Error(t, err, "not compressed")
+}
+
+func TestReadWithStats(t *testing.T) {
+	input := "# duplicates\nDL3NEY\nN1MM\ndl3ney\nW1AW\nN1MM\nDL3NEY\n"
+
+	database, stats, err := ReadWithStats(strings.NewReader(input), SCPFormat)
+	require.NoError(t, err)
+	assert.Equal(t, 6, stats.Entries)
+	assert.Equal(t, []string{"DL3NEY", "N1MM", "DL3NEY"}, stats.Duplicates)
+	assert.Equal(t, 3, database.Len())
+	assert.Equal(t, 0, stats.Skipped)
+
+	_, stats, err = ReadWithStats(strings.NewReader("DL3NEY\nN1MM\n"), SCPFormat)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Empty(t, stats.Duplicates)
+
+	_, stats, err = ReadWithStats(strings.NewReader("; comment\nDL3NEY =NFlorian\n=NOrphan\n\n=SCT\nK1ABC\n"), TRMasterFormat)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 2, stats.Skipped)
+
+	_, stats, err = ReadWithStats(strings.NewReader("// version 1\nW1AW\n"), NewSCPFormat("//"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, 0, stats.Skipped, "custom comment prefix")
+}
+
+func TestReadWithErrorCallback(t *testing.T) {
+	tt := []struct {
+		desc     string
+		input    string
+		parser   EntryParser
+		expected map[int]string
+		entries  int
+	}{
+		{"scp", "# comment\nDL3NEY\n\nN1MM\n", SCPFormat, map[int]string{}, 2},
+		{"scp with comment prefix", "// comment\nDL3NEY\n\nN1MM\n", NewSCPFormat("//"), map[int]string{}, 2},
+		{"trmaster", "; comment\nDL3NEY =NFlorian\n=NOrphan\n# comment\nK1ABC\n  =SCT\n", TRMasterFormat, map[int]string{3: "=NOrphan", 6: "  =SCT"}, 2},
+		{"call history", "!!Order!!,Name,Call\nFlorian,DL3NEY\n# comment\nNobody\n", NewCallHistoryParser(), map[int]string{4: "Nobody"}, 1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			actual := make(map[int]string)
+			database, err := ReadWithErrorCallback(strings.NewReader(tc.input), tc.parser, func(lineNo int, line string) {
+				actual[lineNo] = line
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+			assert.Equal(t, tc.entries, database.Len())
+		})
+	}
+}
+
+func TestReadStrict(t *testing.T) {
+	database, err := ReadStrict(strings.NewReader("; comment\nDL3NEY =NFlorian\n\n# comment\nK1ABC =SCT\n"), TRMasterFormat)
+	require.NoError(t, err)
+	assert.Equal(t, 2, database.Len())
+
+	database, err = ReadStrict(strings.NewReader("; comment\nDL3NEY =NFlorian\n=NOrphan\nK1ABC =SCT\n"), TRMasterFormat)
+	assert.Nil(t, database)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+	assert.Contains(t, err.Error(), "=NOrphan")
+
+	database, err = ReadStrict(strings.NewReader("// version 1\nW1AW\n"), NewSCPFormat("//"))
+	require.NoError(t, err, "custom comment prefix")
+	assert.Equal(t, 1, database.Len())
+}
+
+func TestReadWithMaxLineLength(t *testing.T) {
+	longValue := strings.Repeat("x", 100*1024)
+	database, err := ReadCallHistory(strings.NewReader("!!Order!!,Call,UserText\nDL3NEY," + longValue + "\nW1AW,short\n"))
+	require.NoError(t, err, "lines longer than the default buffer of bufio.Scanner")
+	assert.Equal(t, 2, database.Len())
+	fields, _ := database.Get("DL3NEY")
+	assert.Equal(t, longValue, fields[FieldUserText])
+
+	_, err = Read(strings.NewReader("DL3NEY\n"+strings.Repeat("W", DefaultMaxLineLength+1)+"\nW1AW\n"), SCPFormat)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+
+	tt := []struct {
+		desc  string
+		input string
+		valid bool
+	}{
+		{"max length", "DL3NEY\n0123456789\r\nW1AW", true},
+		{"max length at the end", "DL3NEY\n0123456789", true},
+		{"too long", "DL3NEY\n0123456789X\nW1AW", false},
+		{"too long at the end", "DL3NEY\n0123456789X", false},
+		{"no line breaks", strings.Repeat("0123456789", 100), false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.desc, func(t *testing.T) {
+			database, err := ReadWithMaxLineLength(strings.NewReader(tc.input), SCPFormat, 10)
+			if tc.valid {
+				require.NoError(t, err)
+				assert.True(t, database.Contains("0123456789"))
+			} else {
+				require.Error(t, err)
+				assert.Nil(t, database)
+			}
+		})
+	}
+}
+
+func TestDatabase_FindN(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := Read(file, SCPFormat)
+	require.NoError(t, err)
+
+	all, err := database.Find("DL1AB")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+
+	for n := 0; n <= 3; n++ {
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			actual, err := database.FindN("DL1AB", n)
+			require.NoError(t, err)
+			expected := all
+			if n < len(all) {
+				expected = all[:n]
+			}
+			assert.ElementsMatch(t, expected, actual)
+			assert.Len(t, actual, len(expected))
+		})
+	}
+}
+
+func TestDatabase_FindBest(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := Read(file, SCPFormat)
+	require.NoError(t, err)
+
+	all := mustFind(t, database, "DL1AB")
+	require.NotEmpty(t, all)
+
+	best, ok := database.FindBest("DL1AB")
+	assert.True(t, ok)
+	assert.Equal(t, all[0], best)
+
+	_, ok = database.FindBest("QQ9QQQQ")
+	assert.False(t, ok, "no match")
+	_, ok = database.FindBest("DL")
+	assert.False(t, ok, "too short")
+}
+
+func TestDatabase_Exact(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName)
+	database.Add("DL3NEY", "DL3NEY", "Florian")
+	database.Add("DL1ABC", "DL1ABC", "Klaus")
+
+	tt := []struct {
+		key   string
+		found bool
+	}{
+		{"DL3NEY", true},
+		{" dl3ney ", true},
+		{"DL3NE", false},
+		{"DL3NEYY", false},
+		{"", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.key, func(t *testing.T) {
+			assert.Equal(t, tc.found, database.Contains(tc.key))
+
+			match, found := database.Exact(tc.key)
+			assert.Equal(t, tc.found, found)
+			if tc.found {
+				assert.Equal(t, "DL3NEY", match.Key())
+				assert.Equal(t, "Florian", match.Get(FieldUserName))
+				assert.Equal(t, 1.0, match.Accuracy())
+				assert.Equal(t, "DL3NEY", match.Assembly.String())
+			}
+		})
+	}
+}
+
+func TestDatabase_MinQueryLength(t *testing.T) {
+	database := NewDatabase()
+	database.Add("CT")
+	database.Add("CTX")
+	assert.Equal(t, DefaultMinQueryLength, database.MinQueryLength())
+
+	actual, err := database.FindStrings("CT")
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+
+	database.SetMinQueryLength(2)
+	assert.Equal(t, 2, database.MinQueryLength())
+	actual, err = database.FindStrings("CT")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CT"}, actual)
+
+	database.SetMinQueryLength(0)
+	assert.Equal(t, DefaultMinQueryLength, database.MinQueryLength())
+}
+
+func TestStripPunctuation(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected string
+	}{
+		{"W1AW", "W1AW"},
+		{"<W1AW>,", "W1AW"},
+		{"'dl3ney'", "dl3ney"},
+		{"W1AW/P", "W1AW/P"},
+		{"...", ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, StripPunctuation(tc.value))
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected string
+	}{
+		{"W1AW", "W1AW"},
+		{" w1aw\t", "W1AW"},
+		{"<W1AW>,", "W1AW"},
+		{"'dl3ney'", "DL3NEY"},
+		{" <dl/w1aw/p>, ", "DL/W1AW/P"},
+		{"W1AW/", "W1AW"},
+		{"W1AW.X", "W1AW.X"},
+		{"...", ""},
+		{"", ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Normalize(tc.value))
+		})
+	}
+}
+
+func TestDatabase_SetNormalization(t *testing.T) {
+	database := NewDatabase()
+	database.Add("W1AW.")
+	database.Add("DL3NEY")
+	assert.False(t, database.Contains("W1AW"))
+
+	database.SetNormalization(true)
+	assert.True(t, database.Contains("W1AW"))
+	assert.True(t, database.Contains("w1aw,"))
+	assert.False(t, database.Contains("W1AW.X"))
+
+	database.Add("(DL1ABC)")
+	actual, err := database.FindStrings("'dl1abc'")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC"}, actual)
+}
+
+func TestStripPortable(t *testing.T) {
+	tt := []struct {
+		call     string
+		expected string
+	}{
+		{"W1AW", "W1AW"},
+		{"W1AW/P", "W1AW"},
+		{"w1aw/m", "W1AW"},
+		{"W1AW/QRP", "W1AW"},
+		{"W1AW/7", "W1AW"},
+		{"DL/W1AW", "W1AW"},
+		{"DL/W1AW/M", "W1AW"},
+		{"VP2E/W1AW/MM", "W1AW"},
+		{"XX/NOCALL", "NOCALL"},
+		{"", ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.call, func(t *testing.T) {
+			assert.Equal(t, tc.expected, StripPortable(tc.call))
+		})
+	}
+}
+
+func TestValidGrid(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected bool
+	}{
+		{"JO", true},
+		{"JO62", true},
+		{"jo62qm", true},
+		{"JO62QM12", true},
+		{" FN31 ", true},
+		{"", false},
+		{"J", false},
+		{"JO6", false},
+		{"SS62", false},
+		{"JO62ZZ", false},
+		{"JO62qm12ab", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ValidGrid(tc.value))
+		})
+	}
+}
+
+func TestBaseCall(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected string
+	}{
+		{"W1AW", "W1AW"},
+		{" w1aw ", "W1AW"},
+		{"DL/W1AW/P", "W1AW"},
+		{"KH6/W1AW", "W1AW"},
+		{"W1AW/KH6", "W1AW"},
+		{"VP2E/W1AW/QRPP", "W1AW"},
+		{"XXXXXXX/W1AW", "W1AW"},
+		{"W1AW/1234567", "W1AW"},
+		{"DL/W1AW/P/QRP", "W1AW"},
+		{"DL1ABC-2", "DL1ABC-2"},
+		{"XX/NOCALL", "NOCALL"},
+		{"/", ""},
+		{"", ""},
+	}
+	for _, tc := range tt {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, BaseCall(tc.value))
+		})
+	}
+}
+
+func TestIsValidCallsign(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected bool
+	}{
+		{"DL1ABC", true},
+		{"dl1abc", true},
+		{" W1AW ", true},
+		{"3DA0RU", true},
+		{"K/DL1ABC/P", true},
+		{"W1AW/7", true},
+		{"", false},
+		{"DLABC", false},
+		{"12345", false},
+		{"DL1", false},
+		{"DL1AB!", false},
+		{"DL1 ABC", false},
+		{"DL1ÄBC", false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsValidCallsign(tc.value))
+		})
+	}
+}
+
+func TestDatabase_SetMatchBaseCall(t *testing.T) {
+	database := NewDatabase()
+	database.Add("W1AW")
+	database.Add("DL3NEY")
+
+	actual, err := database.FindStrings("DL/W1AW/M")
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+
+	database.SetMatchBaseCall(true)
+	for _, call := range []string{"DL/W1AW/M", "DL/W1AW", "W1AW/7", "W1AW/P"} {
+		actual, err := database.FindStrings(call)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"W1AW"}, actual, call)
+	}
+}
+
+func TestDatabase_FindContext(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.Add("DL2ABC")
+
+	actual, err := database.FindContext(context.Background(), "DLABC")
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	actual, err = database.FindContext(ctx, "DLABC")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, actual)
+}
+
+func TestDatabase_FindStream(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := Read(file, SCPFormat)
+	require.NoError(t, err)
+
+	expected, err := database.Find("DK1ABC")
+	require.NoError(t, err)
+
+	stream, err := database.FindStream(context.Background(), "DK1ABC")
+	require.NoError(t, err)
+	actual := make([]Match, 0)
+	for match := range stream {
+		actual = append(actual, match)
+	}
+	assert.ElementsMatch(t, expected, actual)
+
+	stream, err = database.FindStream(context.Background(), "DK")
+	require.NoError(t, err)
+	_, open := <-stream
+	assert.False(t, open, "too short")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = database.FindStream(ctx, "DK1ABC")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestMatch_Highlight(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.Add("DL2ABC")
+	database.Add("DL3NEY")
+
+	tt := []struct {
+		input    string
+		expected []string
+	}{
+		{"DL1ABC", []string{"[DL1ABC]", "[DL]2[ABC]"}},
+		{"DLABC", []string{"[DL]1[ABC]", "[DL]2[ABC]"}},
+		{"DL3NE", []string{"[DL3NE]Y"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.input, func(t *testing.T) {
+			matches, err := database.Find(tc.input)
+			require.NoError(t, err)
+			actual := make([]string, len(matches))
+			for i, match := range matches {
+				actual[i] = match.Highlight()
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMatch_MatchedRanges(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.Add("DL2ABC")
+	database.Add("DL3NEY")
+	database.Add("DL1MÜLLER")
+
+	tt := []struct {
+		input    string
+		expected map[string][][2]int
+	}{
+		{"DL1ABC", map[string][][2]int{"DL1ABC": {{0, 6}}, "DL2ABC": {{0, 2}, {3, 6}}}},
+		{"DLABC", map[string][][2]int{"DL1ABC": {{0, 2}, {3, 6}}, "DL2ABC": {{0, 2}, {3, 6}}}},
+		{"DL3NE", map[string][][2]int{"DL3NEY": {{0, 5}}}},
+		{"DL1MULLER", map[string][][2]int{"DL1MÜLLER": {{0, 10}}}},
+		{"DL1MULER", map[string][][2]int{"DL1MÜLLER": {{0, 7}, {8, 10}}}},
+		{"DL1MXLLER", map[string][][2]int{"DL1MÜLLER": {{0, 4}, {6, 10}}}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.input, func(t *testing.T) {
+			matches, err := database.Find(tc.input)
+			require.NoError(t, err)
+			actual := make(map[string][][2]int, len(matches))
+			for _, match := range matches {
+				actual[match.Key()] = match.MatchedRanges()
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	assert.Empty(t, Match{}.MatchedRanges())
+}
+
+func TestDatabase_FindWithScorer(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.Add("DL2ABC")
+	database.Add("DL3NEY")
+
+	prefixScorer := ScorerFunc(func(input, key string) (int, float64, MatchingAssembly) {
+		if !strings.HasPrefix(key, input) {
+			return len(key), 0, nil
+		}
+		return len(key) - len(input), 1, MatchingAssembly{{NOP, input}, {Insert, key[len(input):]}}
+	})
+
+	matches, err := database.FindWithScorer("DL3", prefixScorer)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "DL3NEY", matches[0].Key())
+	assert.Equal(t, "[DL3]NEY", matches[0].Highlight())
+
+	expected, err := database.Find("DLABC")
+	require.NoError(t, err)
+	actual, err := database.FindWithScorer("DLABC", EditDistanceScorer)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestDatabase_SetParallelism(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := Read(file, SCPFormat)
+	require.NoError(t, err)
+	assert.Equal(t, runtime.NumCPU(), database.Parallelism())
+
+	expected, err := database.Find("DK1ABC")
+	require.NoError(t, err)
+
+	for _, parallelism := range []int{1, 2, 100} {
+		t.Run(fmt.Sprintf("%d", parallelism), func(t *testing.T) {
+			database.SetParallelism(parallelism)
+			assert.Equal(t, parallelism, database.Parallelism())
+
+			actual, err := database.Find("DK1ABC")
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestDatabase_SetWeightField(t *testing.T) {
+	database := NewDatabase(FieldCall, "Frequent")
+	database.Add("DL1ABC", "DL1ABC", "")
+	database.Add("DL2ABC", "DL2ABC", "5")
+	database.Add("DL3ABC", "DL3ABC", "invalid")
+
+	actual, err := database.FindStrings("DLABC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC", "DL2ABC", "DL3ABC"}, actual)
+
+	database.SetWeightField("Frequent")
+	matches, err := database.Find("DLABC")
+	require.NoError(t, err)
+	require.Len(t, matches, 3)
+	assert.Equal(t, "DL2ABC", matches[0].Key())
+	assert.Equal(t, 5.0, matches[0].Weight())
+	assert.Equal(t, "DL1ABC", matches[1].Key())
+	assert.Equal(t, 0.0, matches[1].Weight())
+	assert.Equal(t, "DL3ABC", matches[2].Key())
+	assert.Equal(t, 0.0, matches[2].Weight())
+
+	matches, err = database.Find("DL1ABC")
+	require.NoError(t, err)
+	assert.Equal(t, "DL1ABC", matches[0].Key(), "accuracy goes first")
+}
+
+func TestDatabase_FindFiltered(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldState)
+	database.Add("W8ABC", "W8ABC", "OH")
+	database.Add("W8ABD", "W8ABD", "MI")
+	database.Add("W8ABE", "W8ABE", "")
+	database.Add("W8ABF")
+
+	tt := []struct {
+		value    string
+		expected []string
+	}{
+		{"OH", []string{"W8ABC"}},
+		{"MI", []string{"W8ABD"}},
+		{"", []string{"W8ABE"}},
+		{"CT", []string{}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.value, func(t *testing.T) {
+			matches, err := database.FindFiltered("W8AB", FieldState, tc.value)
+			require.NoError(t, err)
+			actual := make([]string, len(matches))
+			for i, match := range matches {
+				actual[i] = match.Key()
+				state, ok := match.Field(FieldState)
+				assert.True(t, ok)
+				assert.Equal(t, tc.value, state)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestMatch_Percent(t *testing.T) {
+	tt := []struct {
+		accuracy accuracy
+		expected int
+	}{
+		{0, 0},
+		{0.654, 65},
+		{0.655, 66},
+		{0.8181818181818182, 82},
+		{0.999, 100},
+		{1, 100},
+	}
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%v", tc.accuracy), func(t *testing.T) {
+			assert.Equal(t, tc.expected, Match{accuracy: tc.accuracy}.Percent())
+		})
+	}
+}
+
+func TestDatabase_SetTieBreaker(t *testing.T) {
+	database := NewDatabase(FieldCall, "LastWorked")
+	database.Add("DL1ABC", "DL1ABC", "2021-06-01")
+	database.Add("DL2ABC", "DL2ABC", "2023-02-18")
+	database.Add("DL3ABC", "DL3ABC", "")
+
+	actual, err := database.FindStrings("DLABC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC", "DL2ABC", "DL3ABC"}, actual)
+
+	database.SetTieBreaker("LastWorked")
+	actual, err = database.FindStrings("DLABC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL2ABC", "DL1ABC", "DL3ABC"}, actual)
+
+	actual, err = database.FindStrings("DL3ABC")
+	require.NoError(t, err)
+	assert.Equal(t, "DL3ABC", actual[0], "accuracy goes first")
+}
+
+func TestDatabase_Remove(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.Add("DL2ABC")
+
+	assert.False(t, database.Remove("DL3ABC"))
+	assert.True(t, database.Remove("dl1abc"))
+	assert.False(t, database.Contains("DL1ABC"))
+	assert.False(t, database.Remove("DL1ABC"))
+
+	for b, es := range database.items {
+		_, ok := es["DL1ABC"]
+		assert.False(t, ok, "bucket %q", string(b))
+	}
+	_, ok := database.items['1']
+	assert.False(t, ok, "empty buckets are removed")
+
+	actual, err := database.FindStrings("DL1ABC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL2ABC"}, actual)
+	assert.Equal(t, []string{"DL2ABC"}, database.FindByPrefix("DL"))
+}
+
+func TestDatabase_Len(t *testing.T) {
+	file, err := os.Open("testdata/MASTER.SCP")
+	require.NoError(t, err)
+	defer file.Close()
+	database, err := Read(file, SCPFormat)
+	require.NoError(t, err)
+	assert.Equal(t, 5, database.Len())
+
+	database.Add("DL1ABC")
+	assert.Equal(t, 5, database.Len(), "duplicate")
+	database.Add("DL3NEY")
+	assert.Equal(t, 6, database.Len())
+	database.Remove("DK1AB")
+	assert.Equal(t, 5, database.Len())
+	assert.Equal(t, 0, NewDatabase().Len())
+}
+
+func TestDatabase_Stats(t *testing.T) {
+	database := NewDatabase()
+	assert.Equal(t, DatabaseStats{BucketSizes: map[byte]int{}}, database.Stats())
+
+	database.Add("DL1ABC")
+	database.Add("DL2ABC")
+	database.Add("W1AW")
+	database.Add("DL1ABC")
+
+	actual := database.Stats()
+	assert.Equal(t, 3, actual.Entries)
+	assert.Equal(t, 8, actual.Buckets)
+	assert.Equal(t, map[byte]int{'D': 2, 'L': 2, '1': 2, '2': 1, 'A': 3, 'B': 2, 'C': 2, 'W': 1}, actual.BucketSizes)
+	assert.Equal(t, database.Len(), actual.Entries)
+}
+
+func TestDatabase_ApproxSizeBytes(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName, FieldSection)
+	assert.Equal(t, int64(0), database.ApproxSizeBytes())
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i := 0; i < 20000; i++ {
+		database.Add(fmt.Sprintf("%c%c%d%c%c%c", 'A'+i%26, 'A'+(i/26)%26, i%10, 'A'+(i/260)%26, 'A'+(i/6760)%26, 'A'+i%7), "", fmt.Sprintf("Name%d", i), "EMA")
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	used := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+
+	actual := database.ApproxSizeBytes()
+	assert.Greater(t, actual, used/4, "used %d bytes", used)
+	assert.Less(t, actual, used*4, "used %d bytes", used)
+
+	database.Clear()
+	assert.Equal(t, int64(0), database.ApproxSizeBytes())
+}
+
+func TestDatabase_Each(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName)
+	database.Add("DL3NEY", "DL3NEY", "Florian")
+	database.Add("DL1ABC", "DL1ABC", "Klaus")
+	database.Add("N1MM", "N1MM", "")
+
+	keys := make([]string, 0)
+	names := make([]string, 0)
+	database.Each(func(key string, fields FieldValues) bool {
+		keys = append(keys, key)
+		names = append(names, fields[FieldUserName])
+		return true
+	})
+	assert.Equal(t, []string{"DL1ABC", "DL3NEY", "N1MM"}, keys)
+	assert.Equal(t, []string{"Klaus", "Florian", ""}, names)
+
+	count := 0
+	database.Each(func(string, FieldValues) bool {
+		count++
+		return count < 2
+	})
+	assert.Equal(t, 2, count, "stop early")
+}
+
+func TestDatabase_Get(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName)
+	database.Add("DL3NEY", "DL3NEY", "Florian")
+	database.Add("N1MM")
+
+	fields, ok := database.Get("dl3ney")
+	require.True(t, ok)
+	assert.Equal(t, FieldValues{FieldUserName: "Florian"}, fields)
+
+	fields[FieldUserName] = "modified"
+	fields, _ = database.Get("DL3NEY")
+	assert.Equal(t, "Florian", fields[FieldUserName], "returns a copy")
+
+	fields, ok = database.Get("N1MM")
+	require.True(t, ok)
+	assert.Empty(t, fields)
+
+	_, ok = database.Get("DL1ABC")
+	assert.False(t, ok)
+}
+
+func TestDatabase_Clear(t *testing.T) {
+	database, err := ReadCallHistory(strings.NewReader("# comment\n!!Order!!,Call,Name\nDL3NEY,Florian\n"))
+	require.NoError(t, err)
+	database.SetMinQueryLength(2)
+	items := database.items
+
+	database.Clear()
+	assert.Equal(t, 0, database.Len())
+	assert.Empty(t, database.Comments())
+	assert.Empty(t, database.FindByPrefix("DL"))
+	assert.Equal(t, FieldSet{FieldCall, FieldUserName}, database.FieldSet())
+	assert.Equal(t, 2, database.MinQueryLength())
+
+	database.Add("DL1ABC", "DL1ABC", "Klaus")
+	assert.Equal(t, 1, len(items['L']), "the items are reused")
+}
+
+func TestDatabase_Concurrency(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			database.Add(fmt.Sprintf("DL%dABC", i))
+			database.Remove(fmt.Sprintf("DL%dABC", i-1))
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, err := database.FindStrings("DLABC")
+		require.NoError(t, err)
+		database.Contains("DL1ABC")
+		database.FindByPrefix("DL")
+	}
+	<-done
+
+	assert.Equal(t, []string{"DL99ABC"}, database.FindByPrefix("DL"))
+}
+
+func TestDatabase_ModifyWhileStreaming(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.Add("DL2ABC")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := database.FindStream(ctx, "DL1ABC")
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range stream {
+			database.Add("DL3ABC")
+			database.Remove("DL2ABC")
+		}
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("the database is locked by the stream")
+	}
+
+	assert.Equal(t, []string{"DL1ABC", "DL3ABC"}, mustFindStrings(t, database, "DL1ABC"))
+}
+
+func TestMatch_Field(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName, FieldSection)
+	database.Add("DL3NEY", "DL3NEY", "Florian", "")
+	database.Add("DL3NEX")
+
+	matches, err := database.Find("DL3NEY")
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+
+	value, ok := matches[0].Field(FieldUserName)
+	assert.True(t, ok)
+	assert.Equal(t, "Florian", value)
+	value, ok = matches[0].Field(FieldOpName)
+	assert.True(t, ok)
+	assert.Equal(t, "Florian", value)
+	value, ok = matches[0].Field(FieldSection)
+	assert.True(t, ok)
+	assert.Equal(t, "", value)
+	_, ok = matches[0].Field("Unknown")
+	assert.False(t, ok)
+	_, ok = matches[1].Field(FieldUserName)
+	assert.False(t, ok)
+}
+
+func TestDatabase_SetFields(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName, "State")
+	database.Add("W1AW", "W1AW", "Hiram", "CT")
+	database.Add("N1MM")
+
+	assert.True(t, database.SetFields("w1aw", FieldValues{"State": "MA"}))
+	fields, _ := database.Get("W1AW")
+	assert.Equal(t, FieldValues{FieldUserName: "Hiram", "State": "MA"}, fields)
+	for _, b := range []byte("W1A") {
+		assert.Equal(t, "MA", database.items[b]["W1AW"].Get("State"), "bucket %q", string(b))
+	}
+
+	assert.True(t, database.SetFields("N1MM", FieldValues{FieldUserName: "Tom"}))
+	fields, _ = database.Get("N1MM")
+	assert.Equal(t, FieldValues{FieldUserName: "Tom"}, fields)
+
+	assert.False(t, database.SetFields("DL3NEY", FieldValues{FieldUserName: "Florian"}))
+	assert.False(t, database.Contains("DL3NEY"))
+}
+
+func BenchmarkDatabase_Add_Duplicates(b *testing.B) {
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("DL%dA%c%d", i%10, 'A'+i%26, i/260)
+	}
+	database := NewDatabase()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		database.Add(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkFind(b *testing.B) {
+	database := NewDatabase()
+	for i := 0; i < 20000; i++ {
+		database.Add(fmt.Sprintf("%c%c%d%c%c%c", 'A'+i%26, 'A'+(i/26)%26, i%10, 'A'+(i/260)%26, 'A'+(i/6760)%26, 'A'+i%7))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := database.Find("DL1ABC")
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFind_MinFingerprintOverlap(b *testing.B) {
+	database := NewDatabase()
+	for i := 0; i < 20000; i++ {
+		database.Add(fmt.Sprintf("%c%c%d%c%c%c", 'A'+i%26, 'A'+(i/26)%26, i%10, 'A'+(i/260)%26, 'A'+(i/6760)%26, 'A'+i%7))
+	}
+	for _, overlap := range []int{1, 3, 5} {
+		b.Run(fmt.Sprintf("%d", overlap), func(b *testing.B) {
+			database.SetMinFingerprintOverlap(overlap)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := database.Find("KH6/DL1ABCXYZ/QRP")
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDatabase_Close(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.SetParallelism(2)
+	assert.Nil(t, database.workers, "started with the first search")
+
+	actual, err := database.FindStrings("DL1ABC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC"}, actual)
+	workers := database.workers
+	require.NotNil(t, workers)
+
+	_, err = database.FindStrings("DL2ABC")
+	require.NoError(t, err)
+	assert.Same(t, workers, database.workers, "reused for all searches")
+
+	database.Close()
+	assert.Nil(t, database.workers)
+
+	actual, err = database.FindStrings("DL1ABC")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC"}, actual, "usable after close")
+	database.Close()
+}
+
+func TestDatabase_FindWhileStreaming(t *testing.T) {
+	database := NewDatabase()
+	database.SetParallelism(2)
+	for i := 0; i < 500; i++ {
+		database.Add(fmt.Sprintf("DL%dABC", i))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := database.FindStream(ctx, "DL1ABC")
+	require.NoError(t, err)
+	<-stream
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := database.Find("DL2ABC")
+		assert.NoError(t, err)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		t.Fatal("Find is blocked by the unread stream")
+	}
+
+	for range stream {
+	}
+}
+
+func TestDatabase_IdleWorkersStop(t *testing.T) {
+	defaultTimeout := workerIdleTimeout
+	workerIdleTimeout = 10 * time.Millisecond
+	defer func() { workerIdleTimeout = defaultTimeout }()
+
+	baseline := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		database := NewDatabase()
+		database.SetParallelism(2)
+		database.Add("DL1ABC")
+		database.Add("DL2ABC")
+		database.Add("DL3ABC")
+		_, err := database.Find("DL1ABC")
+		require.NoError(t, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+}
+
+func TestDatabase_Find_MoreThanBufferedMatches(t *testing.T) {
+	database := NewDatabase()
+	for a := 'A'; a <= 'Z'; a++ {
+		for b := 'A'; b <= 'Z'; b++ {
+			database.Add(fmt.Sprintf("DL1AB%c%c", a, b))
+		}
+	}
+	database.SetParallelism(1)
+
+	done := make(chan []Match)
+	go func() {
+		matches, err := database.Find("DL1AB")
+		assert.NoError(t, err)
+		done <- matches
+	}()
+
+	select {
+	case matches := <-done:
+		assert.Len(t, matches, 26*26)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Find is blocked")
+	}
+}
+
+func TestDatabase_AddChecked(t *testing.T) {
+	database := NewDatabase(FieldCall, FieldUserName)
+
+	assert.NoError(t, database.AddChecked("DL3NEY", "DL3NEY", "Florian"))
+	fields, _ := database.Get("DL3NEY")
+	assert.Equal(t, FieldValues{FieldUserName: "Florian"}, fields)
+
+	assert.NoError(t, database.AddChecked("N1MM"))
+	assert.True(t, database.Contains("N1MM"))
+
+	assert.Error(t, database.AddChecked("DL1ABC", "Klaus"))
+	assert.Error(t, database.AddChecked("DL1ABC", "DL1ABC", "Klaus", "B01"))
+	assert.False(t, database.Contains("DL1ABC"))
+}
+
+func TestDatabase_Find_AccentedCharacters(t *testing.T) {
+	database := NewDatabase()
+	database.Add("SP9ŁÓDŹ")
+	database.Add("DL1MÜLLER")
+
+	tt := []struct {
+		input    string
+		expected []string
+	}{
+		{"SP9LODZ", []string{"SP9ŁÓDŹ"}},
+		{"sp9łódź", []string{"SP9ŁÓDŹ"}},
+		{"DL1MULLER", []string{"DL1MÜLLER"}},
+		{"DL1MÜLER", []string{"DL1MÜLLER"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.input, func(t *testing.T) {
+			actual, err := database.FindStrings(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+	assert.True(t, database.Contains("dl1müller"))
+}
+
+func TestDatabase_FindWithScorer_Transposition(t *testing.T) {
+	database := NewDatabase()
+	database.Add("W1AW")
+	database.Add("DL3NEY")
+
+	actual, err := database.FindWithScorer("W1WA", EditDistanceScorer)
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+
+	actual, err = database.FindWithScorer("W1WA", TranspositionScorer)
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "W1AW", actual[0].Key())
+	assert.Equal(t, 0.75, actual[0].Accuracy())
+}
+
+func TestDatabase_SetMinFingerprintOverlap(t *testing.T) {
+	database := NewDatabase()
+	database.Add("DL1ABC")
+	database.Add("DL1ABCD")
+	database.Add("DL2ABC")
+	database.Add("KH6XYZ")
+	database.Add("W1AW")
+	assert.Equal(t, 1, database.MinFingerprintOverlap())
+
+	all := mustFind(t, database, "DL1ABC")
+	require.Len(t, all, 3)
+
+	for _, overlap := range []int{0, 1, 3, 5} {
+		t.Run(fmt.Sprintf("%d", overlap), func(t *testing.T) {
+			database.SetMinFingerprintOverlap(overlap)
+			actual := mustFind(t, database, "DL1ABC")
+			assert.Equal(t, all, actual)
+		})
+	}
+
+	database.SetMinFingerprintOverlap(100)
+	assert.Equal(t, 100, database.MinFingerprintOverlap())
+	actual := mustFind(t, database, "DL1ABC")
+	assert.Equal(t, []string{"DL1ABC", "DL1ABCD"}, []string{actual[0].Key(), actual[1].Key()}, "all distinct characters are required")
+	assert.Len(t, actual, 2)
+
+	database.SetMinFingerprintOverlap(1)
+	actual, err := database.FindWithThreshold("Q1Q", 0.1)
+	require.NoError(t, err)
+	assert.Contains(t, matchKeys(actual), "W1AW")
+
+	database.SetMinFingerprintOverlap(2)
+	actual, err = database.FindWithThreshold("Q1Q", 0.1)
+	require.NoError(t, err)
+	assert.NotContains(t, matchKeys(actual), "W1AW", "W1AW has only 1 character in common with Q1Q")
+	assert.Equal(t, 2, database.Clone().MinFingerprintOverlap())
+}
+
+func matchKeys(matches []Match) []string {
+	result := make([]string, len(matches))
+	for i, match := range matches {
+		result[i] = match.Key()
+	}
+	return result
+}
+
+func TestNewDatabaseWith(t *testing.T) {
+	database := NewDatabaseWith()
+	assert.Equal(t, FieldSet{}, database.FieldSet())
+	assert.Equal(t, DefaultAccuracyThreshold, database.Threshold())
+	assert.Equal(t, DefaultMinQueryLength, database.MinQueryLength())
+	assert.Equal(t, runtime.NumCPU(), database.Parallelism())
+
+	database = NewDatabaseWith(
+		WithFields(FieldCall, FieldUserName),
+		WithThreshold(0.8),
+		WithMinQueryLen(2),
+		WithNormalization(),
+		WithParallelism(2),
+	)
+	assert.Equal(t, FieldSet{FieldCall, FieldUserName}, database.FieldSet())
+	assert.Equal(t, 0.8, database.Threshold())
+	assert.Equal(t, 2, database.MinQueryLength())
+	assert.Equal(t, 2, database.Parallelism())
+
+	database.Add("<DL1ABC>", "DL1ABC", "Klaus")
+	database.Add("DL1ABCD", "DL1ABCD", "Hans")
+	actual, err := database.FindStrings("'DL1AB'")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC"}, actual, "only close matches with the normalized key")
+	matches, err := database.FindWithThreshold("DL1AB", DefaultAccuracyThreshold)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DL1ABC", "DL1ABCD"}, matchKeys(matches), "the default threshold")
+
+	assert.Equal(t, NewDatabase(FieldCall, FieldUserName), NewDatabaseWith(WithFields(FieldCall, FieldUserName)))
+	assert.Equal(t, NewDatabase(), NewDatabaseWith())
+}
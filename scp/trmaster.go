@@ -0,0 +1,108 @@
+package scp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrBinaryTRMaster is returned by ReadTRMasterASCII if the given data is not text, e.g. a binary TRMASTER.DTA file.
+var ErrBinaryTRMaster = errors.New("binary TRMASTER.DTA format is not supported, use TRMASTER.ASC")
+
+// TRMasterFieldSet defines the fields that are read from a TRMASTER file. The fields are mapped from the
+// tags of the TRMASTER format as follows:
+//
+//	=N name        -> Name
+//	=S section     -> Sect
+//	=C check       -> CK
+//	=Q QTH         -> QTH
+//	=G grid square -> Grid
+//	=K CQ zone     -> CQZone
+//	=I ITU zone    -> ITUZone
+//
+// Other tags are ignored.
+var TRMasterFieldSet = FieldSet{FieldCall, FieldUserName, FieldSection, FieldCheck, "QTH", FieldGrid, FieldCQZone, FieldITUZone}
+
+var trMasterTags = map[byte]FieldName{
+	'N': FieldUserName,
+	'S': FieldSection,
+	'C': FieldCheck,
+	'Q': "QTH",
+	'G': FieldGrid,
+	'K': FieldCQZone,
+	'I': FieldITUZone,
+}
+
+// ReadTRMasterASCII creates a new Database and fills it from the ASCII representation of a TRMASTER file of TR Log
+// (TRMASTER.ASC) that is read with the given reader. Each line contains one callsign, followed by tagged values
+// separated by whitespace, e.g. "DL3NEY =NFlorian =K14 =I28". See TRMasterFieldSet for the supported tags.
+//
+// The binary TRMASTER.DTA format is not supported, its layout is not publicly documented. If the data contains
+// control characters, ReadTRMasterASCII returns ErrBinaryTRMaster.
+func ReadTRMasterASCII(r io.Reader) (*Database, error) {
+	return readTRMaster(r, TRMasterFieldSet)
+}
+
+// readTRMaster creates a new Database with the given field set and fills it from a TRMASTER file. Only the tags
+// that correspond to the fields of the given field set are kept.
+func readTRMaster(r io.Reader, fieldSet FieldSet) (*Database, error) {
+	reader := bufio.NewReader(r)
+	if isBinary(reader) {
+		return nil, ErrBinaryTRMaster
+	}
+	result, err := Read(reader, newTRMasterFormat(fieldSet))
+	if err != nil {
+		return nil, err
+	}
+	result.fieldSet = fieldSet
+	return result, nil
+}
+
+// binaryDetectionLength is the number of bytes at the beginning of the data that are checked by isBinary.
+const binaryDetectionLength = 512
+
+// isBinary returns true if the beginning of the data contains control characters other than tab, line breaks, and
+// the end-of-file marker of DOS.
+func isBinary(r *bufio.Reader) bool {
+	data, _ := r.Peek(binaryDetectionLength)
+	for _, b := range data {
+		switch {
+		case b == '\t', b == '\n', b == '\r', b == 0x1A:
+			continue
+		case b < 0x20, b == 0x7F:
+			return true
+		}
+	}
+	return false
+}
+
+// TRMasterFormat parses the entries of a TRMASTER.ASC file.
+var TRMasterFormat = newTRMasterFormat(TRMasterFieldSet)
+
+// newTRMasterFormat returns an EntryParser for TRMASTER.ASC files that keeps only the tags that correspond to
+// the fields of the given field set.
+func newTRMasterFormat(fieldSet FieldSet) EntryParserFunc {
+	return func(line string) (Entry, bool) {
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			return Entry{}, false
+		}
+		words := strings.Fields(line)
+		if len(words) == 0 || strings.HasPrefix(words[0], "=") {
+			return Entry{}, false
+		}
+
+		fieldValues := make(FieldValues)
+		for _, word := range words[1:] {
+			if len(word) < 3 || word[0] != '=' {
+				continue
+			}
+			fieldName, ok := trMasterTags[strings.ToUpper(word[1:2])[0]]
+			if !ok || fieldSet.IndexOf(fieldName) < 0 {
+				continue
+			}
+			fieldValues[fieldName] = word[2:]
+		}
+		return newEntry(words[0], fieldValues), true
+	}
+}
@@ -0,0 +1,51 @@
+package scp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadTRMasterASCII(t *testing.T) {
+	const testTRMaster = `; a comment
+DL3NEY =NFlorian =K14 =I28 =GJO62
+K1ABC =SCT =C85 =XUNKNOWN
+=NOrphan
+W1AW`
+
+	database, err := ReadTRMasterASCII(strings.NewReader(testTRMaster))
+	require.NoError(t, err)
+	assert.Equal(t, TRMasterFieldSet, database.FieldSet())
+
+	tt := []struct {
+		key      string
+		expected FieldValues
+	}{
+		{"DL3NEY", FieldValues{"Name": "Florian", FieldCQZone: "14", FieldITUZone: "28", FieldGrid: "JO62"}},
+		{"K1ABC", FieldValues{FieldSection: "CT", FieldCheck: "85"}},
+		{"W1AW", FieldValues{}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.key, func(t *testing.T) {
+			matches, err := database.Find(tc.key)
+			require.NoError(t, err)
+			require.NotEmpty(t, matches)
+			assert.Equal(t, tc.key, matches[0].Key())
+			assert.Equal(t, tc.expected, matches[0].fieldValues)
+		})
+	}
+	assert.Empty(t, mustFindStrings(t, database, "ORPHAN"))
+}
+
+func TestReadTRMasterASCII_Binary(t *testing.T) {
+	database, err := ReadTRMasterASCII(strings.NewReader("DL3NEY\x00\x02\x1f\x00K1ABC\x00\x00"))
+	assert.Nil(t, database)
+	assert.Equal(t, ErrBinaryTRMaster, err)
+
+	database, err = ReadTRMasterASCII(strings.NewReader("DL3NEY =NFlorian\tx\r\nW1AW\r\n\x1a"))
+	require.NoError(t, err, "text with DOS line breaks")
+	assert.Equal(t, []string{"DL3NEY"}, mustFindStrings(t, database, "DL3NEY"))
+	assert.Equal(t, []string{"W1AW"}, mustFindStrings(t, database, "W1AW"))
+}
@@ -0,0 +1,107 @@
+package scp
+
+import (
+	"sync"
+	"time"
+)
+
+// workerIdleTimeout is the time after which an idle worker goroutine stops.
+var workerIdleTimeout = 10 * time.Second
+
+// workerPool runs jobs with a limited number of goroutines. The goroutines are started on demand and stop when
+// they were idle for workerIdleTimeout. If all goroutines are busy, submit waits until one of the jobs is complete.
+type workerPool struct {
+	jobs  chan func()
+	slots chan struct{}
+	done  chan struct{}
+
+	idleTimeout time.Duration
+}
+
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{
+		jobs:        make(chan func()),
+		slots:       make(chan struct{}, size),
+		done:        make(chan struct{}),
+		idleTimeout: workerIdleTimeout,
+	}
+}
+
+func (p *workerPool) submit(job func()) {
+	p.slots <- struct{}{}
+	slotted := func() {
+		defer func() { <-p.slots }()
+		job()
+	}
+
+	select {
+	case p.jobs <- slotted:
+	default:
+		go p.work(slotted)
+	}
+}
+
+func (p *workerPool) work(job func()) {
+	for {
+		job()
+
+		idle := time.NewTimer(p.idleTimeout)
+		select {
+		case job = <-p.jobs:
+			idle.Stop()
+		case <-idle.C:
+			return
+		case <-p.done:
+			idle.Stop()
+			return
+		}
+	}
+}
+
+// run executes the given jobs with this pool and waits until all jobs are complete.
+func (p *workerPool) run(jobs []func()) {
+	waiter := &sync.WaitGroup{}
+	waiter.Add(len(jobs))
+	for _, job := range jobs {
+		job := job
+		p.submit(func() {
+			defer waiter.Done()
+			job()
+		})
+	}
+	waiter.Wait()
+}
+
+func (p *workerPool) close() {
+	close(p.done)
+}
+
+// workerPool returns the worker pool of this database. The pool is started with the first search.
+func (d *Database) workerPool() *workerPool {
+	d.workersLock.Lock()
+	defer d.workersLock.Unlock()
+	if d.workers == nil {
+		d.workers = newWorkerPool(d.effectiveParallelism())
+	}
+	return d.workers
+}
+
+// stopWorkers stops the goroutines of the worker pool, if it was started.
+func (d *Database) stopWorkers() {
+	d.workersLock.Lock()
+	defer d.workersLock.Unlock()
+	if d.workers == nil {
+		return
+	}
+	d.workers.close()
+	d.workers = nil
+}
+
+// Close stops the goroutines that are used to search the database. Idle goroutines also stop by themselves after
+// a while, Close only releases them immediately. The database remains usable, the next search starts new goroutines.
+// Close waits until all running searches are complete.
+func (d *Database) Close() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.stopWorkers()
+}
@@ -0,0 +1,39 @@
+package scp
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPool_Run(t *testing.T) {
+	pool := newWorkerPool(2)
+	defer pool.close()
+
+	lock := &sync.Mutex{}
+	var running, maxRunning, completed int
+	jobs := make([]func(), 20)
+	for i := range jobs {
+		jobs[i] = func() {
+			lock.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			lock.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			lock.Lock()
+			running--
+			completed++
+			lock.Unlock()
+		}
+	}
+	pool.run(jobs)
+
+	assert.Equal(t, len(jobs), completed)
+	assert.LessOrEqual(t, maxRunning, 2)
+}